@@ -0,0 +1,237 @@
+// Package ackqueue 提供了带可见性超时和重新投递的确认队列实现，语义类似 SQS。
+package ackqueue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/Repeater11/go-template/structure/queue"
+)
+
+// Clock 抽象了确认队列使用的时间源，测试中可以替换为手动推进的时钟。
+type Clock interface {
+	// Now 返回当前时间。
+	Now() time.Time
+}
+
+// systemClock 是基于 time 包的真实时钟。
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Message 是投递给消费者的一条消息。
+type Message[T any] struct {
+	ID           uint64
+	Body         T
+	ReceiveCount int // 包括本次在内被接收的次数
+}
+
+// message 是队列内部保存的消息状态。
+type message[T any] struct {
+	Message[T]
+	deadline time.Time // 不可见状态的截止时间
+	index    int       // 在 inflight 堆中的下标，可见时为 -1
+}
+
+// deadlineHeap 按不可见截止时间实现 heap.Interface。
+type deadlineHeap[T any] []*message[T]
+
+func (h deadlineHeap[T]) Len() int { return len(h) }
+
+func (h deadlineHeap[T]) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h deadlineHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *deadlineHeap[T]) Push(x any) {
+	m := x.(*message[T])
+	m.index = len(*h)
+	*h = append(*h, m)
+}
+
+func (h *deadlineHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	m := old[n-1]
+	old[n-1] = nil // 清零防止内存泄漏
+	m.index = -1
+	*h = old[:n-1]
+	return m
+}
+
+// AckQueue 是一个并发安全的确认队列。
+// 被 Receive 取出的消息在可见性超时内对其他消费者不可见，
+// 超时前未被 Ack 的消息会重新变为可见并再次投递。
+// 接收次数达到上限的消息会被移入死信队列。
+type AckQueue[T any] struct {
+	mu         sync.Mutex
+	clock      Clock
+	maxReceive int
+	nextID     uint64
+	visible    *queue.Queue[*message[T]]
+	inflight   deadlineHeap[T]
+	messages   map[uint64]*message[T] // 所有可见和不可见的消息
+	deadLetter *queue.Queue[Message[T]]
+}
+
+// NewAckQueue 创建一个使用系统时钟的空 AckQueue。
+// maxReceiveCount 为每条消息最多被接收的次数，不为正数时表示不限制。
+func NewAckQueue[T any](maxReceiveCount int) *AckQueue[T] {
+	return NewAckQueueWithClock[T](maxReceiveCount, systemClock{})
+}
+
+// NewAckQueueWithClock 创建一个使用指定时钟的空 AckQueue。
+// maxReceiveCount 为每条消息最多被接收的次数，不为正数时表示不限制。
+func NewAckQueueWithClock[T any](maxReceiveCount int, clock Clock) *AckQueue[T] {
+	return &AckQueue[T]{
+		clock:      clock,
+		maxReceive: maxReceiveCount,
+		visible:    queue.NewQueue[*message[T]](),
+		messages:   make(map[uint64]*message[T]),
+		deadLetter: queue.NewQueue[Message[T]](),
+	}
+}
+
+// Len 返回队列中尚未确认的消息数量，包括不可见的消息。
+func (q *AckQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.messages)
+}
+
+// Visible 返回当前可以被接收的消息数量。
+func (q *AckQueue[T]) Visible() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expire()
+	return q.visible.Len()
+}
+
+// InFlight 返回已被接收但尚未确认且未超时的消息数量。
+func (q *AckQueue[T]) InFlight() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expire()
+	return q.inflight.Len()
+}
+
+// Push 在队列尾部添加一条消息，返回其 ID。
+func (q *AckQueue[T]) Push(body T) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	m := &message[T]{
+		Message: Message[T]{ID: q.nextID, Body: body},
+		index:   -1,
+	}
+	q.messages[m.ID] = m
+	q.visible.Push(m)
+	return m.ID
+}
+
+// Receive 最多接收 n 条可见消息，并使其在 visibility 时间内不可见。
+// 接收次数已达上限的消息不会被投递，而是移入死信队列。
+// 如果没有可见消息，返回空切片。
+func (q *AckQueue[T]) Receive(n int, visibility time.Duration) []Message[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expire()
+
+	now := q.clock.Now()
+	result := []Message[T]{}
+	for len(result) < n {
+		m, ok := q.visible.Pop()
+		if !ok {
+			break
+		}
+		if q.maxReceive > 0 && m.ReceiveCount >= q.maxReceive {
+			delete(q.messages, m.ID)
+			q.deadLetter.Push(m.Message)
+			continue
+		}
+		m.ReceiveCount++
+		m.deadline = now.Add(visibility)
+		heap.Push(&q.inflight, m)
+		result = append(result, m.Message)
+	}
+	return result
+}
+
+// Ack 确认并删除一条已接收的消息。
+// 如果消息不存在、未被接收或可见性已超时，返回 false。
+func (q *AckQueue[T]) Ack(id uint64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	m := q.receivedMessage(id)
+	if m == nil {
+		return false
+	}
+	heap.Remove(&q.inflight, m.index)
+	delete(q.messages, id)
+	return true
+}
+
+// Nack 拒绝一条已接收的消息，使其立即重新可见。
+// 如果消息不存在、未被接收或可见性已超时，返回 false。
+func (q *AckQueue[T]) Nack(id uint64) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	m := q.receivedMessage(id)
+	if m == nil {
+		return false
+	}
+	heap.Remove(&q.inflight, m.index)
+	q.visible.Push(m)
+	return true
+}
+
+// ExtendVisibility 将一条已接收消息的可见性超时重置为从现在起的 d。
+// 如果消息不存在、未被接收或可见性已超时，返回 false。
+func (q *AckQueue[T]) ExtendVisibility(id uint64, d time.Duration) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	m := q.receivedMessage(id)
+	if m == nil {
+		return false
+	}
+	m.deadline = q.clock.Now().Add(d)
+	heap.Fix(&q.inflight, m.index)
+	return true
+}
+
+// DeadLetterLen 返回死信队列中的消息数量。
+func (q *AckQueue[T]) DeadLetterLen() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.deadLetter.Len()
+}
+
+// PopDeadLetter 移除并返回死信队列中最早的一条消息。
+// 如果死信队列为空，返回零值和 false。
+func (q *AckQueue[T]) PopDeadLetter() (Message[T], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.deadLetter.Pop()
+}
+
+// receivedMessage 返回处于不可见状态的消息，调用者需持有锁。
+func (q *AckQueue[T]) receivedMessage(id uint64) *message[T] {
+	q.expire()
+	m, ok := q.messages[id]
+	if !ok || m.index < 0 {
+		return nil
+	}
+	return m
+}
+
+// expire 按截止时间顺序将所有超时的消息重新放入可见队列，调用者需持有锁。
+func (q *AckQueue[T]) expire() {
+	now := q.clock.Now()
+	for q.inflight.Len() > 0 && !q.inflight[0].deadline.After(now) {
+		q.visible.Push(heap.Pop(&q.inflight).(*message[T]))
+	}
+}
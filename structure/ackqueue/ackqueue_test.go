@@ -0,0 +1,216 @@
+package ackqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 是只能手动推进的时钟。
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestQueue(maxReceive int) (*AckQueue[string], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return NewAckQueueWithClock[string](maxReceive, clock), clock
+}
+
+func bodies(msgs []Message[string]) []string {
+	result := make([]string, len(msgs))
+	for i, m := range msgs {
+		result[i] = m.Body
+	}
+	return result
+}
+
+func TestReceiveAck(t *testing.T) {
+	q, _ := newTestQueue(0)
+	q.Push("a")
+	q.Push("b")
+	q.Push("c")
+
+	msgs := q.Receive(2, time.Minute)
+	if len(msgs) != 2 || msgs[0].Body != "a" || msgs[1].Body != "b" {
+		t.Fatalf("unexpected messages %v", bodies(msgs))
+	}
+	if msgs[0].ReceiveCount != 1 {
+		t.Fatalf("expected receive count 1, got %d", msgs[0].ReceiveCount)
+	}
+	if q.Visible() != 1 || q.InFlight() != 2 || q.Len() != 3 {
+		t.Fatalf("unexpected counts visible=%d inflight=%d len=%d", q.Visible(), q.InFlight(), q.Len())
+	}
+
+	if !q.Ack(msgs[0].ID) {
+		t.Fatal("Ack of in-flight message should succeed")
+	}
+	if q.Ack(msgs[0].ID) {
+		t.Fatal("second Ack should fail")
+	}
+	if q.Ack(999) {
+		t.Fatal("Ack of unknown message should fail")
+	}
+	if q.Len() != 2 {
+		t.Fatalf("expected len 2 after Ack, got %d", q.Len())
+	}
+
+	if got := q.Receive(10, time.Minute); len(got) != 1 || got[0].Body != "c" {
+		t.Fatalf("expected only c to be visible, got %v", bodies(got))
+	}
+	if got := q.Receive(10, time.Minute); len(got) != 0 {
+		t.Fatalf("expected no visible messages, got %v", bodies(got))
+	}
+}
+
+func TestVisibilityTimeoutRedelivery(t *testing.T) {
+	q, clock := newTestQueue(0)
+	id := q.Push("job")
+
+	q.Receive(1, 30*time.Second)
+	clock.Advance(29 * time.Second)
+	if got := q.Receive(1, 30*time.Second); len(got) != 0 {
+		t.Fatal("message should stay invisible before the timeout")
+	}
+
+	clock.Advance(time.Second)
+	got := q.Receive(1, 30*time.Second)
+	if len(got) != 1 || got[0].ID != id || got[0].ReceiveCount != 2 {
+		t.Fatalf("expected redelivery with receive count 2, got %+v", got)
+	}
+}
+
+func TestAckAfterTimeoutFails(t *testing.T) {
+	q, clock := newTestQueue(0)
+	id := q.Push("job")
+	q.Receive(1, time.Second)
+	clock.Advance(time.Second)
+
+	if q.Ack(id) {
+		t.Fatal("Ack after visibility timeout should fail")
+	}
+	if q.ExtendVisibility(id, time.Minute) {
+		t.Fatal("ExtendVisibility after timeout should fail")
+	}
+	if q.Visible() != 1 {
+		t.Fatal("expired message should be visible again")
+	}
+}
+
+func TestNack(t *testing.T) {
+	q, _ := newTestQueue(0)
+	q.Push("a")
+	q.Push("b")
+	msgs := q.Receive(1, time.Hour)
+
+	if !q.Nack(msgs[0].ID) {
+		t.Fatal("Nack of in-flight message should succeed")
+	}
+	if q.Nack(msgs[0].ID) {
+		t.Fatal("Nack of visible message should fail")
+	}
+	if got := bodies(q.Receive(2, time.Hour)); len(got) != 2 || got[0] != "b" || got[1] != "a" {
+		t.Fatalf("nacked message should go to the back, got %v", got)
+	}
+}
+
+func TestExtendVisibility(t *testing.T) {
+	q, clock := newTestQueue(0)
+	id := q.Push("long job")
+	q.Receive(1, 10*time.Second)
+
+	clock.Advance(8 * time.Second)
+	if !q.ExtendVisibility(id, 10*time.Second) {
+		t.Fatal("ExtendVisibility should succeed")
+	}
+	clock.Advance(5 * time.Second)
+	if q.Visible() != 0 {
+		t.Fatal("extended message should still be invisible")
+	}
+	clock.Advance(5 * time.Second)
+	if q.Visible() != 1 {
+		t.Fatal("message should be visible after the extended timeout")
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	q, clock := newTestQueue(2)
+	q.Push("poison")
+
+	for i := 0; i < 2; i++ {
+		got := q.Receive(1, time.Second)
+		if len(got) != 1 || got[0].Body != "poison" {
+			t.Fatalf("attempt %d expected poison, got %v", i+1, bodies(got))
+		}
+		q.Nack(got[0].ID)
+	}
+	q.Push("ok")
+
+	got := q.Receive(1, time.Second)
+	if len(got) != 1 || got[0].Body != "ok" {
+		t.Fatalf("poison should be skipped, got %v", bodies(got))
+	}
+	if q.DeadLetterLen() != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", q.DeadLetterLen())
+	}
+	dead, ok := q.PopDeadLetter()
+	if !ok || dead.Body != "poison" || dead.ReceiveCount != 2 {
+		t.Fatalf("unexpected dead letter %+v", dead)
+	}
+	if _, ok := q.PopDeadLetter(); ok {
+		t.Fatal("dead letter queue should be empty")
+	}
+	clock.Advance(time.Second)
+	if q.Len() != 1 {
+		t.Fatalf("expected only ok to remain, got len %d", q.Len())
+	}
+}
+
+func TestConcurrentConsumers(t *testing.T) {
+	q := NewAckQueue[int](0)
+	const n = 1000
+	for i := 0; i < n; i++ {
+		q.Push(i)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	var wg sync.WaitGroup
+	for c := 0; c < 4; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				msgs := q.Receive(10, time.Hour)
+				if len(msgs) == 0 {
+					return
+				}
+				for _, m := range msgs {
+					mu.Lock()
+					if seen[m.Body] {
+						t.Errorf("message %d delivered twice", m.Body)
+					}
+					seen[m.Body] = true
+					mu.Unlock()
+					q.Ack(m.ID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != n || q.Len() != 0 {
+		t.Fatalf("expected %d messages acked, got %d (len %d)", n, len(seen), q.Len())
+	}
+}
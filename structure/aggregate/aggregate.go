@@ -0,0 +1,246 @@
+// Package aggregate 提供了支持 O(1) 查询最小值、最大值和幺半群聚合值的栈与队列实现。
+package aggregate
+
+import (
+	"cmp"
+
+	"github.com/Repeater11/go-template/structure/stack"
+)
+
+// frame 是栈中的一层，保存元素以及从栈底到该层（含）的聚合结果。
+type frame[T any] struct {
+	value T
+	min   T
+	max   T
+	agg   T
+}
+
+// Stack 是一个在每次 Push 时维护运行聚合值的栈，Min、Max 和 Aggregate 都是 O(1) 的。
+// 聚合运算 op 需要满足结合律，例如求和、求积、最大公约数或字符串拼接；
+// 它不必满足交换律，Aggregate 按从栈底到栈顶的顺序计算。
+type Stack[T any] struct {
+	cmp     func(a, b T) int
+	op      func(a, b T) T
+	reverse bool // 为 true 时按从栈顶到栈底的顺序聚合，供 Queue 的出队栈使用
+	frames  *stack.Stack[frame[T]]
+}
+
+// NewStack 创建一个空的聚合栈。
+// cmp 用于比较元素：a < b 时返回负数，a == b 时返回 0，a > b 时返回正数。
+// op 是满足结合律的聚合运算，为 nil 时 Aggregate 总是返回 false。
+func NewStack[T any](cmp func(a, b T) int, op func(a, b T) T) *Stack[T] {
+	return &Stack[T]{
+		cmp:    cmp,
+		op:     op,
+		frames: stack.NewStack[frame[T]](),
+	}
+}
+
+// NewOrderedStack 创建一个元素类型为有序类型的空聚合栈。
+func NewOrderedStack[T cmp.Ordered](op func(a, b T) T) *Stack[T] {
+	return NewStack(cmp.Compare[T], op)
+}
+
+// Len 返回栈中元素的数量。
+func (s *Stack[T]) Len() int {
+	return s.frames.Len()
+}
+
+// IsEmpty 检查栈是否为空。
+func (s *Stack[T]) IsEmpty() bool {
+	return s.frames.IsEmpty()
+}
+
+// Push 压入一个元素到栈顶。
+func (s *Stack[T]) Push(elem T) {
+	f := frame[T]{value: elem, min: elem, max: elem, agg: elem}
+	if below, ok := s.frames.Top(); ok {
+		if s.cmp(below.min, elem) < 0 {
+			f.min = below.min
+		}
+		if s.cmp(below.max, elem) > 0 {
+			f.max = below.max
+		}
+		if s.op != nil {
+			if s.reverse {
+				f.agg = s.op(elem, below.agg)
+			} else {
+				f.agg = s.op(below.agg, elem)
+			}
+		}
+	}
+	s.frames.Push(f)
+}
+
+// Pop 弹出并返回栈顶元素。
+// 如果栈为空，返回零值和 false。
+func (s *Stack[T]) Pop() (T, bool) {
+	f, ok := s.frames.Pop()
+	return f.value, ok
+}
+
+// Top 返回栈顶元素但不移除它。
+// 如果栈为空，返回零值和 false。
+func (s *Stack[T]) Top() (T, bool) {
+	f, ok := s.frames.Top()
+	return f.value, ok
+}
+
+// Min 返回栈中的最小值。
+// 如果栈为空，返回零值和 false。
+func (s *Stack[T]) Min() (T, bool) {
+	f, ok := s.frames.Top()
+	return f.min, ok
+}
+
+// Max 返回栈中的最大值。
+// 如果栈为空，返回零值和 false。
+func (s *Stack[T]) Max() (T, bool) {
+	f, ok := s.frames.Top()
+	return f.max, ok
+}
+
+// Aggregate 返回按从栈底到栈顶的顺序对所有元素应用 op 的结果。
+// 如果栈为空或没有提供 op，返回零值和 false。
+func (s *Stack[T]) Aggregate() (T, bool) {
+	var zero T
+	f, ok := s.frames.Top()
+	if !ok || s.op == nil {
+		return zero, false
+	}
+	return f.agg, true
+}
+
+// Clear 清空栈中的所有元素。
+func (s *Stack[T]) Clear() {
+	s.frames.Clear()
+}
+
+// ToSlice 返回从栈底到栈顶的所有元素。
+func (s *Stack[T]) ToSlice() []T {
+	frames := s.frames.ToSlice()
+	result := make([]T, len(frames))
+	for i, f := range frames {
+		result[i] = f.value
+	}
+	return result
+}
+
+// Queue 是由两个聚合栈组成的先进先出队列，Min、Max 和 Aggregate 都是 O(1) 的，
+// Pop 和 Front 的均摊时间复杂度为 O(1)。
+// 新元素压入入队栈，出队栈为空时将入队栈的元素全部倒入出队栈。
+// Aggregate 按从队头到队尾的顺序计算，因此 op 同样只需满足结合律。
+type Queue[T any] struct {
+	cmp func(a, b T) int
+	op  func(a, b T) T
+	in  *Stack[T]
+	out *Stack[T] // 栈顶是队头
+}
+
+// NewQueue 创建一个空的聚合队列，参数含义与 NewStack 相同。
+func NewQueue[T any](cmp func(a, b T) int, op func(a, b T) T) *Queue[T] {
+	out := NewStack(cmp, op)
+	out.reverse = true
+	return &Queue[T]{
+		cmp: cmp,
+		op:  op,
+		in:  NewStack(cmp, op),
+		out: out,
+	}
+}
+
+// NewOrderedQueue 创建一个元素类型为有序类型的空聚合队列。
+func NewOrderedQueue[T cmp.Ordered](op func(a, b T) T) *Queue[T] {
+	return NewQueue(cmp.Compare[T], op)
+}
+
+// Len 返回队列中元素的数量。
+func (q *Queue[T]) Len() int {
+	return q.in.Len() + q.out.Len()
+}
+
+// IsEmpty 检查队列是否为空。
+func (q *Queue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// Push 在队列尾部添加一个元素。
+func (q *Queue[T]) Push(elem T) {
+	q.in.Push(elem)
+}
+
+// Pop 移除并返回队列头部的元素。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Pop() (T, bool) {
+	q.transfer()
+	return q.out.Pop()
+}
+
+// Front 返回队列头部的元素但不移除它。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Front() (T, bool) {
+	q.transfer()
+	return q.out.Top()
+}
+
+// Min 返回队列中的最小值。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Min() (T, bool) {
+	return q.combine(q.in.Min, q.out.Min, func(a, b T) T {
+		if q.cmp(b, a) < 0 {
+			return b
+		}
+		return a
+	})
+}
+
+// Max 返回队列中的最大值。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Max() (T, bool) {
+	return q.combine(q.in.Max, q.out.Max, func(a, b T) T {
+		if q.cmp(b, a) > 0 {
+			return b
+		}
+		return a
+	})
+}
+
+// Aggregate 返回按从队头到队尾的顺序对所有元素应用 op 的结果。
+// 如果队列为空或没有提供 op，返回零值和 false。
+func (q *Queue[T]) Aggregate() (T, bool) {
+	return q.combine(q.in.Aggregate, q.out.Aggregate, q.op)
+}
+
+// Clear 清空队列中的所有元素。
+func (q *Queue[T]) Clear() {
+	q.in.Clear()
+	q.out.Clear()
+}
+
+// combine 合并出队栈（较早的元素）与入队栈（较晚的元素）的查询结果。
+func (q *Queue[T]) combine(inFn, outFn func() (T, bool), merge func(a, b T) T) (T, bool) {
+	early, okOut := outFn()
+	late, okIn := inFn()
+	switch {
+	case okOut && okIn:
+		return merge(early, late), true
+	case okOut:
+		return early, true
+	default:
+		return late, okIn
+	}
+}
+
+// transfer 在出队栈为空时将入队栈的元素全部倒入出队栈。
+func (q *Queue[T]) transfer() {
+	if !q.out.IsEmpty() {
+		return
+	}
+	for {
+		elem, ok := q.in.Pop()
+		if !ok {
+			return
+		}
+		q.out.Push(elem)
+	}
+}
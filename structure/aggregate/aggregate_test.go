@@ -0,0 +1,141 @@
+package aggregate
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func add(a, b int) int { return a + b }
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func concat(a, b string) string { return a + b }
+
+func TestStack(t *testing.T) {
+	s := NewOrderedStack(add)
+	if _, ok := s.Min(); ok {
+		t.Fatal("Min on empty stack should return false")
+	}
+	if _, ok := s.Aggregate(); ok {
+		t.Fatal("Aggregate on empty stack should return false")
+	}
+
+	for _, x := range []int{5, 3, 8, 1, 9} {
+		s.Push(x)
+	}
+	check := func(wantMin, wantMax, wantSum int) {
+		t.Helper()
+		minV, _ := s.Min()
+		maxV, _ := s.Max()
+		sum, _ := s.Aggregate()
+		if minV != wantMin || maxV != wantMax || sum != wantSum {
+			t.Fatalf("expected min %d max %d sum %d, got %d %d %d", wantMin, wantMax, wantSum, minV, maxV, sum)
+		}
+	}
+	check(1, 9, 26)
+	if top, _ := s.Pop(); top != 9 {
+		t.Fatalf("expected 9, got %d", top)
+	}
+	check(1, 8, 17)
+	s.Pop()
+	check(3, 8, 16)
+	s.Pop()
+	check(3, 5, 8)
+	if top, _ := s.Top(); top != 3 || s.Len() != 2 {
+		t.Fatalf("expected top 3 and len 2, got %d %d", top, s.Len())
+	}
+	if got := s.ToSlice(); !slices.Equal(got, []int{5, 3}) {
+		t.Fatalf("unexpected slice %v", got)
+	}
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Fatal("stack should be empty after Clear")
+	}
+}
+
+func TestStackOrderedAggregate(t *testing.T) {
+	s := NewOrderedStack(concat)
+	for _, x := range []string{"a", "b", "c"} {
+		s.Push(x)
+	}
+	if got, _ := s.Aggregate(); got != "abc" {
+		t.Fatalf("expected bottom-to-top aggregate abc, got %q", got)
+	}
+}
+
+func TestStackWithoutOp(t *testing.T) {
+	s := NewOrderedStack[int](nil)
+	s.Push(1)
+	if _, ok := s.Aggregate(); ok {
+		t.Fatal("Aggregate without op should return false")
+	}
+	if minV, ok := s.Min(); !ok || minV != 1 {
+		t.Fatal("Min should work without op")
+	}
+}
+
+func TestQueueMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	q := NewOrderedQueue(gcd)
+	var model []int
+	for i := 0; i < 3000; i++ {
+		if len(model) == 0 || r.Intn(3) > 0 {
+			x := (r.Intn(20) + 1) * 6
+			q.Push(x)
+			model = append(model, x)
+		} else {
+			got, ok := q.Pop()
+			if !ok || got != model[0] {
+				t.Fatalf("step %d: expected %d, got %d", i, model[0], got)
+			}
+			model = model[1:]
+		}
+		if q.Len() != len(model) {
+			t.Fatalf("step %d: expected len %d, got %d", i, len(model), q.Len())
+		}
+		if len(model) == 0 {
+			continue
+		}
+		want := 0
+		for _, x := range model {
+			want = gcd(want, x)
+		}
+		minV, _ := q.Min()
+		maxV, _ := q.Max()
+		g, _ := q.Aggregate()
+		if minV != slices.Min(model) || maxV != slices.Max(model) || g != want {
+			t.Fatalf("step %d: expected min %d max %d gcd %d, got %d %d %d",
+				i, slices.Min(model), slices.Max(model), want, minV, maxV, g)
+		}
+	}
+}
+
+func TestQueueOrderedAggregate(t *testing.T) {
+	q := NewOrderedQueue(concat)
+	var model []string
+	for i, x := range strings.Split("abcdefghij", "") {
+		q.Push(x)
+		model = append(model, x)
+		if i%3 == 2 {
+			q.Pop()
+			model = model[1:]
+		}
+		if got, _ := q.Aggregate(); got != strings.Join(model, "") {
+			t.Fatalf("expected front-to-back aggregate %q, got %q", strings.Join(model, ""), got)
+		}
+	}
+	if front, _ := q.Front(); front != model[0] {
+		t.Fatalf("expected front %q, got %q", model[0], front)
+	}
+	q.Clear()
+	if _, ok := q.Pop(); ok || !q.IsEmpty() {
+		t.Fatal("queue should be empty after Clear")
+	}
+}
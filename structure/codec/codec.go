@@ -0,0 +1,53 @@
+// Package codec 定义了将元素与字节序列相互转换的编解码器，供需要落盘的容器使用。
+package codec
+
+import "encoding/json"
+
+// Codec 负责将类型 T 的值编码为字节序列以及从字节序列解码。
+type Codec[T any] interface {
+	// Encode 将 v 编码为字节序列。
+	Encode(v T) ([]byte, error)
+	// Decode 从 data 解码出一个值，实现不应持有 data 的引用。
+	Decode(data []byte) (T, error)
+}
+
+// JSON 是基于 encoding/json 的编解码器，适用于任何可以 JSON 序列化的类型。
+type JSON[T any] struct{}
+
+// Encode 将 v 编码为 JSON。
+func (JSON[T]) Encode(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode 从 JSON 解码出一个值。
+func (JSON[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// Bytes 是字节切片的编解码器，原样保存数据。
+type Bytes struct{}
+
+// Encode 原样返回 v。
+func (Bytes) Encode(v []byte) ([]byte, error) {
+	return v, nil
+}
+
+// Decode 返回 data 的副本。
+func (Bytes) Decode(data []byte) ([]byte, error) {
+	return append([]byte{}, data...), nil
+}
+
+// String 是字符串的编解码器，以 UTF-8 字节保存。
+type String struct{}
+
+// Encode 将 v 转换为字节切片。
+func (String) Encode(v string) ([]byte, error) {
+	return []byte(v), nil
+}
+
+// Decode 将 data 转换为字符串。
+func (String) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
@@ -0,0 +1,46 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	type event struct {
+		Name  string
+		Count int
+	}
+	var c Codec[event] = JSON[event]{}
+	data, err := c.Encode(event{"click", 3})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	got, err := c.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if got != (event{"click", 3}) {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+	if _, err := c.Decode([]byte("{")); err == nil {
+		t.Fatal("Decode of invalid JSON should fail")
+	}
+}
+
+func TestBytesDecodeCopies(t *testing.T) {
+	var c Codec[[]byte] = Bytes{}
+	data := []byte("abc")
+	got, _ := c.Decode(data)
+	data[0] = 'x'
+	if !bytes.Equal(got, []byte("abc")) {
+		t.Fatalf("Decode should copy its input, got %q", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	var c Codec[string] = String{}
+	data, _ := c.Encode("héllo")
+	if got, _ := c.Decode(data); got != "héllo" {
+		t.Fatalf("round trip mismatch: %q", got)
+	}
+}
@@ -0,0 +1,158 @@
+// Package delayqueue 提供了基于最小堆的泛型延迟队列实现，元素在到期后才能被取出。
+package delayqueue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock 抽象了延迟队列使用的时间源，测试中可以替换为手动推进的时钟。
+type Clock interface {
+	// Now 返回当前时间。
+	Now() time.Time
+	// After 返回一个在经过 d 之后接收到当前时间的通道。
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock 是基于 time 包的真实时钟。
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// item 是堆中的一个元素。
+type item[T any] struct {
+	value T
+	at    time.Time
+	seq   uint64 // 插入序号，保证到期时间相同的元素按插入顺序取出
+}
+
+// itemHeap 按到期时间实现 heap.Interface。
+type itemHeap[T any] []item[T]
+
+func (h itemHeap[T]) Len() int { return len(h) }
+
+func (h itemHeap[T]) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h itemHeap[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *itemHeap[T]) Push(x any) { *h = append(*h, x.(item[T])) }
+
+func (h *itemHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = item[T]{} // 清零防止内存泄漏
+	*h = old[:n-1]
+	return x
+}
+
+// DelayQueue 是一个并发安全的延迟队列，元素只有在到达指定时间后才能被取出。
+// 到期时间相同的元素按插入顺序取出。
+type DelayQueue[T any] struct {
+	mu     sync.Mutex
+	items  itemHeap[T]
+	seq    uint64
+	clock  Clock
+	notify chan struct{} // 每次插入时关闭并替换，用于唤醒等待中的 Pop
+}
+
+// NewDelayQueue 创建一个使用系统时钟的空 DelayQueue。
+func NewDelayQueue[T any]() *DelayQueue[T] {
+	return NewDelayQueueWithClock[T](systemClock{})
+}
+
+// NewDelayQueueWithClock 创建一个使用指定时钟的空 DelayQueue。
+func NewDelayQueueWithClock[T any](clock Clock) *DelayQueue[T] {
+	return &DelayQueue[T]{
+		clock:  clock,
+		notify: make(chan struct{}),
+	}
+}
+
+// Len 返回队列中元素的数量，包括尚未到期的元素。
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// IsEmpty 检查队列是否为空。
+func (q *DelayQueue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// PushAt 添加一个在时间 at 到期的元素。
+func (q *DelayQueue[T]) PushAt(elem T, at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	heap.Push(&q.items, item[T]{value: elem, at: at, seq: q.seq})
+	q.seq++
+	close(q.notify)
+	q.notify = make(chan struct{})
+}
+
+// PushAfter 添加一个在经过 d 之后到期的元素。
+func (q *DelayQueue[T]) PushAfter(elem T, d time.Duration) {
+	q.PushAt(elem, q.clock.Now().Add(d))
+}
+
+// NextDeadline 返回最早到期元素的到期时间。
+// 如果队列为空，返回零值和 false。
+func (q *DelayQueue[T]) NextDeadline() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return time.Time{}, false
+	}
+	return q.items[0].at, true
+}
+
+// PopReady 移除并按到期顺序返回所有已到期的元素，不会阻塞。
+// 如果没有到期的元素，返回空切片。
+func (q *DelayQueue[T]) PopReady() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.clock.Now()
+	result := []T{}
+	for len(q.items) > 0 && !q.items[0].at.After(now) {
+		result = append(result, heap.Pop(&q.items).(item[T]).value)
+	}
+	return result
+}
+
+// Pop 移除并返回最早到期的元素，在其到期之前阻塞等待。
+// 等待期间插入更早到期的元素会被及时感知。
+// 如果 ctx 在取到元素前被取消，返回零值和 ctx.Err()。
+func (q *DelayQueue[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		var timer <-chan time.Time
+		if len(q.items) > 0 {
+			wait := q.items[0].at.Sub(q.clock.Now())
+			if wait <= 0 {
+				elem := heap.Pop(&q.items).(item[T]).value
+				q.mu.Unlock()
+				return elem, nil
+			}
+			timer = q.clock.After(wait)
+		}
+		notify := q.notify
+		q.mu.Unlock()
+
+		select {
+		case <-timer:
+		case <-notify:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
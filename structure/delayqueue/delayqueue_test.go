@@ -0,0 +1,161 @@
+package delayqueue
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 是只能手动推进的时钟。
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+	added   chan struct{} // 每注册一个等待者发送一次信号
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		added: make(chan struct{}, 100),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, waiter{at: c.now.Add(d), ch: ch})
+	c.added <- struct{}{}
+	return ch
+}
+
+// Advance 推进时钟并触发所有到期的等待者。
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			w.ch <- c.now
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	c.waiters = remaining
+}
+
+func TestPopReady(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueueWithClock[string](clock)
+
+	q.PushAfter("c", 3*time.Second)
+	q.PushAfter("a", time.Second)
+	q.PushAfter("b", 2*time.Second)
+	q.PushAfter("a2", time.Second)
+
+	if got := q.PopReady(); len(got) != 0 {
+		t.Fatalf("nothing should be ready yet, got %v", got)
+	}
+	if at, ok := q.NextDeadline(); !ok || !at.Equal(clock.Now().Add(time.Second)) {
+		t.Fatalf("unexpected next deadline %v", at)
+	}
+
+	clock.Advance(2 * time.Second)
+	if got := q.PopReady(); !slices.Equal(got, []string{"a", "a2", "b"}) {
+		t.Fatalf("expected [a a2 b], got %v", got)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expected 1 pending item, got %d", q.Len())
+	}
+}
+
+func TestPopWaitsForDeadline(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueueWithClock[int](clock)
+	q.PushAfter(42, 5*time.Second)
+
+	result := make(chan int)
+	go func() {
+		v, err := q.Pop(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		result <- v
+	}()
+
+	<-clock.added // Pop 已开始等待
+	clock.Advance(4 * time.Second)
+	select {
+	case v := <-result:
+		t.Fatalf("Pop returned %d before the deadline", v)
+	default:
+	}
+
+	clock.Advance(time.Second)
+	if v := <-result; v != 42 {
+		t.Fatalf("expected 42, got %d", v)
+	}
+}
+
+func TestPopWakesOnEarlierPush(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueueWithClock[string](clock)
+
+	result := make(chan string)
+	go func() {
+		v, _ := q.Pop(context.Background())
+		result <- v
+	}()
+
+	// 队列为空时 Pop 不注册定时器，只等待插入通知
+	q.PushAfter("late", time.Hour)
+	<-clock.added
+	q.PushAt("now", clock.Now())
+
+	if v := <-result; v != "now" {
+		t.Fatalf("expected the earlier item, got %s", v)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expected the late item to remain, got len %d", q.Len())
+	}
+}
+
+func TestPopCancel(t *testing.T) {
+	q := NewDelayQueueWithClock[int](newFakeClock())
+	q.PushAfter(1, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.Pop(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if q.Len() != 1 {
+		t.Fatal("cancelled Pop should not remove items")
+	}
+}
+
+func TestSystemClock(t *testing.T) {
+	q := NewDelayQueue[int]()
+	q.PushAfter(1, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if v, err := q.Pop(ctx); err != nil || v != 1 {
+		t.Fatalf("expected (1,nil), got (%d,%v)", v, err)
+	}
+}
@@ -0,0 +1,23 @@
+package deque
+
+import "context"
+
+// ToChan 启动一个 goroutine，从头部依次取出元素并发送到返回的通道。
+// Deque 为空或 ctx 被取消时关闭通道并退出 goroutine，未发送的元素保留在 Deque 中。
+// 在通道关闭前，调用者不应再以其他方式访问该 Deque。
+func (d *Deque[T]) ToChan(ctx context.Context) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for !d.IsEmpty() {
+			elem, _ := d.Front()
+			select {
+			case ch <- elem:
+				d.PopFront()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
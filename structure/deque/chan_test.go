@@ -0,0 +1,55 @@
+package deque
+
+import (
+	"context"
+	"testing"
+)
+
+// TestToChan 测试将 Deque 的内容依次发送到通道
+func TestToChan(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 5; i++ {
+		d.PushBack(i)
+	}
+
+	i := 0
+	for v := range d.ToChan(context.Background()) {
+		if v != i {
+			t.Errorf("Expected %d, got %d", i, v)
+		}
+		i++
+	}
+	if i != 5 {
+		t.Errorf("Expected 5 elements, got %d", i)
+	}
+	if !d.IsEmpty() {
+		t.Errorf("Expected deque to be drained, got length %d", d.Len())
+	}
+}
+
+// TestToChanCancel 测试取消 ctx 后通道关闭且未发送的元素保留
+func TestToChanCancel(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 5; i++ {
+		d.PushBack(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := d.ToChan(ctx)
+	if v := <-ch; v != 0 {
+		t.Errorf("Expected 0, got %d", v)
+	}
+	cancel()
+	received := 1
+	for range ch {
+		// 取消后 goroutine 仍可能在退出前发送若干元素
+		received++
+	}
+
+	if d.Len() != 5-received {
+		t.Errorf("Expected %d unsent elements to remain, got length %d", 5-received, d.Len())
+	}
+	if front, ok := d.Front(); ok && front != received {
+		t.Errorf("Expected front to be %d, got %d", received, front)
+	}
+}
@@ -0,0 +1,573 @@
+// Package diskqueue 提供了基于预写日志的持久化泛型队列实现，进程重启后可以恢复未消费的元素。
+package diskqueue
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Repeater11/go-template/structure/codec"
+	"github.com/Repeater11/go-template/structure/deque"
+)
+
+const (
+	// segmentExt 是日志段文件的扩展名。
+	segmentExt = ".seg"
+	// headerSize 是记录头的大小：4 字节记录体长度 + 4 字节 CRC32 校验和。
+	headerSize = 8
+	// bodyPrefixSize 是记录体中负载之前的大小：1 字节类型 + 8 字节序号。
+	bodyPrefixSize = 9
+	// defaultSegmentSize 是日志段的默认大小上限。
+	defaultSegmentSize = 64 << 20
+	// defaultWindowSize 是内存窗口默认最多保存的元素数量。
+	defaultWindowSize = 1024
+	// maxRecordSize 是单条记录体的大小上限，用于识别损坏的长度字段。
+	maxRecordSize = 1 << 30
+)
+
+// 记录类型。
+const (
+	recordPush byte = 1
+	recordPop  byte = 2
+)
+
+var (
+	// ErrEmpty 表示队列为空。
+	ErrEmpty = errors.New("diskqueue: queue is empty")
+	// ErrClosed 表示队列已关闭。
+	ErrClosed = errors.New("diskqueue: queue is closed")
+	// ErrCorrupt 表示日志中存在无法恢复的损坏记录。
+	ErrCorrupt = errors.New("diskqueue: corrupt log")
+
+	// errStop 用于提前结束日志段的读取。
+	errStop = errors.New("diskqueue: stop")
+)
+
+// SyncPolicy 决定何时调用 fsync 将日志刷到磁盘。
+type SyncPolicy int
+
+const (
+	// SyncNever 只在切换日志段和关闭时 fsync，进程崩溃不会丢数据，但系统崩溃可能丢失最近的记录。
+	SyncNever SyncPolicy = iota
+	// SyncAlways 在每条记录写入后 fsync，fsync 失败时该记录会从日志中回滚。
+	SyncAlways
+	// SyncBatch 每写入 Options.SyncEvery 条记录 fsync 一次。
+	// fsync 失败时触发它的记录会被回滚，但同一批中之前已返回成功的记录可能没有落盘。
+	SyncBatch
+)
+
+// Options 配置 DiskQueue 的行为，零值表示使用默认配置。
+type Options struct {
+	// SegmentSize 是单个日志段文件的大小上限（字节），超过后切换到新段。默认 64 MiB。
+	SegmentSize int64
+	// Sync 是 fsync 策略，默认 SyncNever。
+	Sync SyncPolicy
+	// SyncEvery 是 SyncBatch 策略下两次 fsync 之间的记录数，默认 1。
+	SyncEvery int
+	// WindowSize 是内存窗口中最多保存的元素数量，超出的元素只保存在日志中，默认 1024。
+	WindowSize int
+}
+
+// segment 描述一个日志段文件。
+type segment struct {
+	id        uint64
+	path      string
+	hasPush   bool
+	firstPush uint64 // 段内最小的入队序号，hasPush 为 false 时无意义
+	lastPush  uint64 // 段内最大的入队序号，hasPush 为 false 时无意义
+}
+
+// DiskQueue 是一个并发安全的持久化先进先出队列。
+// 每次 Push 和 Pop 都会追加一条带 CRC32 校验和的记录到分段的日志文件中，
+// 重新打开时通过重放日志恢复未消费的元素；所有元素都已被消费的旧日志段会被删除。
+// 队列头部最多 Options.WindowSize 个元素同时保存在内存的 Deque 中，
+// 窗口耗尽时再从日志中按顺序读取后续元素，因此内存占用不随积压的元素数量增长。
+type DiskQueue[T any] struct {
+	mu    sync.Mutex
+	dir   string
+	codec codec.Codec[T]
+	opts  Options
+
+	window  *deque.Deque[T] // 序号从 headSeq 开始的连续若干个未消费元素
+	headSeq uint64          // 下一个出队元素的序号
+	tailSeq uint64          // 下一个入队元素的序号
+	readSeg uint64          // 读取位置所在日志段的 id，更早的段中没有尚未放入窗口的元素
+	readOff int64           // 读取位置在 readSeg 中的偏移量，补充窗口时从这里继续读取
+
+	segments   []segment // 按 id 升序排列，最后一个是正在写入的段
+	active     *os.File
+	activeSize int64
+	unsynced   int
+	buf        []byte
+	closed     bool
+	failed     error // 日志无法回滚到一致状态时的错误，之后的所有写操作都会返回它
+	compactErr error // 最近一次压缩日志段失败的错误
+}
+
+// NewDiskQueue 打开或创建位于 dir 的持久化队列，并重放已有日志恢复队列内容。
+// 最后一个日志段末尾不完整或校验失败的记录被视为崩溃时的残缺写入，会被截断；
+// 其他位置的损坏会返回包装了 ErrCorrupt 的错误。
+func NewDiskQueue[T any](dir string, c codec.Codec[T], opts Options) (*DiskQueue[T], error) {
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = defaultSegmentSize
+	}
+	if opts.SyncEvery <= 0 {
+		opts.SyncEvery = 1
+	}
+	if opts.WindowSize <= 0 {
+		opts.WindowSize = defaultWindowSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	q := &DiskQueue[T]{
+		dir:    dir,
+		codec:  c,
+		opts:   opts,
+		window: deque.NewDeque[T](),
+	}
+	if err := q.recover(); err != nil {
+		return nil, err
+	}
+	if err := errors.Join(q.compact(), q.fill()); err != nil {
+		q.active.Close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// Len 返回队列中元素的数量。
+func (q *DiskQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int(q.tailSeq - q.headSeq)
+}
+
+// IsEmpty 检查队列是否为空。
+func (q *DiskQueue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// Front 返回队列头部的元素但不移除它。
+// 如果队列为空，返回零值和 ErrEmpty；内存窗口为空时需要读取日志，读取失败时返回对应的错误。
+func (q *DiskQueue[T]) Front() (T, error) {
+	var zero T
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return zero, ErrClosed
+	}
+	if err := q.fill(); err != nil {
+		return zero, err
+	}
+	elem, ok := q.window.Front()
+	if !ok {
+		return zero, ErrEmpty
+	}
+	return elem, nil
+}
+
+// Push 在队列尾部添加一个元素，写入日志成功后才对读取方可见。
+func (q *DiskQueue[T]) Push(elem T) error {
+	data, err := q.codec.Encode(elem)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	if err := q.append(recordPush, q.tailSeq, data); err != nil {
+		return err
+	}
+	seg := &q.segments[len(q.segments)-1]
+	if !seg.hasPush {
+		seg.hasPush = true
+		seg.firstPush = q.tailSeq
+	}
+	seg.lastPush = q.tailSeq
+	// 只有窗口已经包含到队尾且未满时才放入内存，保证窗口总是队列头部的连续元素
+	if q.headSeq+uint64(q.window.Len()) == q.tailSeq && q.window.Len() < q.opts.WindowSize {
+		q.window.PushBack(elem)
+		// 日志中的所有元素都已消费或在窗口中，下次补充窗口时从当前位置开始读取
+		q.readSeg, q.readOff = seg.id, q.activeSize
+	}
+	q.tailSeq++
+	return nil
+}
+
+// Pop 移除并返回队列头部的元素，出队记录写入日志成功后才会移除。
+// 返回非 nil 错误时元素一定没有被消费。如果队列为空，返回零值和 ErrEmpty。
+// 出队后删除旧日志段失败不会影响本次出队，错误可以通过 Err 获取，并在下次出队时重试。
+func (q *DiskQueue[T]) Pop() (T, error) {
+	var zero T
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return zero, ErrClosed
+	}
+	if err := q.fill(); err != nil {
+		return zero, err
+	}
+	if q.window.IsEmpty() {
+		return zero, ErrEmpty
+	}
+	if err := q.append(recordPop, q.headSeq, nil); err != nil {
+		return zero, err
+	}
+	q.headSeq++
+	elem, _ := q.window.PopFront()
+	q.compactErr = q.compact()
+	return elem, nil
+}
+
+// Err 返回最近一次删除已消费日志段时发生的错误，成功时返回 nil。
+func (q *DiskQueue[T]) Err() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.compactErr
+}
+
+// Sync 将尚未刷盘的日志 fsync 到磁盘。
+func (q *DiskQueue[T]) Sync() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	if q.failed != nil {
+		return q.failed
+	}
+	q.unsynced = 0
+	return q.active.Sync()
+}
+
+// Close 将日志刷盘并关闭队列，之后的所有操作都会返回 ErrClosed。
+func (q *DiskQueue[T]) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	return errors.Join(q.active.Sync(), q.active.Close())
+}
+
+// append 向当前日志段追加一条记录，必要时切换到新段，调用者需持有锁。
+// 写入或 SyncAlways 下的 fsync 失败时，记录会被截断回滚，日志保持写入前的状态；
+// 如果回滚本身失败，队列进入失败状态，之后的写操作都会返回该错误。
+func (q *DiskQueue[T]) append(kind byte, seq uint64, payload []byte) error {
+	if q.failed != nil {
+		return q.failed
+	}
+	if q.activeSize >= q.opts.SegmentSize {
+		if err := q.roll(); err != nil {
+			return err
+		}
+	}
+
+	bodyLen := bodyPrefixSize + len(payload)
+	q.buf = slices.Grow(q.buf[:0], headerSize+bodyLen)[:headerSize+bodyLen]
+	body := q.buf[headerSize:]
+	body[0] = kind
+	binary.LittleEndian.PutUint64(body[1:], seq)
+	copy(body[bodyPrefixSize:], payload)
+	binary.LittleEndian.PutUint32(q.buf[0:], uint32(bodyLen))
+	binary.LittleEndian.PutUint32(q.buf[4:], crc32.ChecksumIEEE(body))
+
+	if _, err := q.active.Write(q.buf); err != nil {
+		return q.rollback(err)
+	}
+
+	q.unsynced++
+	flush := q.opts.Sync == SyncAlways || (q.opts.Sync == SyncBatch && q.unsynced >= q.opts.SyncEvery)
+	if flush {
+		if err := q.active.Sync(); err != nil {
+			q.unsynced--
+			return q.rollback(err)
+		}
+		q.unsynced = 0
+	}
+	q.activeSize += int64(len(q.buf))
+	return nil
+}
+
+// rollback 将当前日志段截断到最后一条成功写入的记录之后，并返回 cause。
+// 截断失败时队列进入失败状态，调用者需持有锁。
+func (q *DiskQueue[T]) rollback(cause error) error {
+	if err := q.active.Truncate(q.activeSize); err != nil {
+		q.failed = fmt.Errorf("diskqueue: log is inconsistent after %w: %w", cause, err)
+		return q.failed
+	}
+	return cause
+}
+
+// roll 关闭当前日志段并创建下一个，调用者需持有锁。
+func (q *DiskQueue[T]) roll() error {
+	if err := q.active.Sync(); err != nil {
+		return err
+	}
+	if err := q.active.Close(); err != nil {
+		q.failed = fmt.Errorf("diskqueue: close segment: %w", err)
+		return q.failed
+	}
+	if err := q.openSegment(q.segments[len(q.segments)-1].id + 1); err != nil {
+		q.failed = fmt.Errorf("diskqueue: open segment: %w", err)
+		return q.failed
+	}
+	return nil
+}
+
+// openSegment 创建一个新的日志段并将其设为当前段。
+func (q *DiskQueue[T]) openSegment(id uint64) error {
+	path := filepath.Join(q.dir, fmt.Sprintf("%020d%s", id, segmentExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	q.active = f
+	q.activeSize = 0
+	q.unsynced = 0
+	q.segments = append(q.segments, segment{id: id, path: path})
+	return nil
+}
+
+// compact 删除最前面的、所有入队元素都已被消费的日志段，当前段除外，调用者需持有锁。
+// 只删除前缀可以保证剩余日志中的出队记录足以推算出消费位置。
+func (q *DiskQueue[T]) compact() error {
+	n := 0
+	for n < len(q.segments)-1 {
+		s := q.segments[n]
+		if s.hasPush && s.lastPush >= q.headSeq {
+			break
+		}
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		n++
+	}
+	if n > 0 {
+		q.segments = slices.Delete(q.segments, 0, n)
+	}
+	return nil
+}
+
+// recover 重放目录中的所有日志段，重建内存中的队列并打开最后一个段用于追加。
+func (q *DiskQueue[T]) recover() error {
+	ids, err := q.listSegments()
+	if err != nil {
+		return err
+	}
+
+	var popped, pushed bool
+	var maxPop, minPush, maxPush uint64
+
+	for i, id := range ids {
+		path := filepath.Join(q.dir, fmt.Sprintf("%020d%s", id, segmentExt))
+		seg := segment{id: id, path: path}
+		last := i == len(ids)-1
+		_, err := readSegment(path, 0, last, func(kind byte, seq uint64, payload []byte) error {
+			switch kind {
+			case recordPush:
+				if !seg.hasPush {
+					seg.hasPush = true
+					seg.firstPush = seq
+				}
+				seg.lastPush = seq
+				if !pushed {
+					pushed = true
+					minPush = seq
+				}
+				maxPush = max(maxPush, seq)
+			case recordPop:
+				popped = true
+				maxPop = max(maxPop, seq)
+			default:
+				return fmt.Errorf("%w: unknown record type %d in %s", ErrCorrupt, kind, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		q.segments = append(q.segments, seg)
+	}
+
+	if popped {
+		q.headSeq = maxPop + 1
+	}
+	if pushed {
+		q.tailSeq = max(maxPush+1, q.headSeq)
+		q.headSeq = max(q.headSeq, minPush)
+	} else {
+		q.tailSeq = q.headSeq
+	}
+
+	if len(q.segments) == 0 {
+		return q.openSegment(0)
+	}
+	// 重新打开最后一个段用于追加
+	last := q.segments[len(q.segments)-1]
+	q.segments = q.segments[:len(q.segments)-1]
+	if err := q.openSegment(last.id); err != nil {
+		return err
+	}
+	q.segments[len(q.segments)-1] = last
+	info, err := q.active.Stat()
+	if err != nil {
+		q.active.Close()
+		return err
+	}
+	q.activeSize = info.Size()
+	return nil
+}
+
+// fill 在内存窗口为空时，从日志中读取序号从 headSeq 开始的最多 WindowSize 个元素，调用者需持有锁。
+// 读取从上次停下的位置继续，因此消费整个日志段只需要顺序读取一遍。
+func (q *DiskQueue[T]) fill() error {
+	if !q.window.IsEmpty() {
+		return nil
+	}
+	next := q.headSeq
+	end := min(q.tailSeq, q.headSeq+uint64(q.opts.WindowSize))
+	readSeg, readOff := q.readSeg, q.readOff
+	for _, s := range q.segments {
+		if next >= end {
+			break
+		}
+		if s.id < q.readSeg || !s.hasPush || s.lastPush < next {
+			continue
+		}
+		var offset int64
+		if s.id == q.readSeg {
+			offset = q.readOff
+		}
+		off, err := readSegment(s.path, offset, false, func(kind byte, seq uint64, payload []byte) error {
+			if kind != recordPush || seq < next {
+				return nil
+			}
+			if seq != next {
+				return fmt.Errorf("%w: missing record %d in %s", ErrCorrupt, next, s.path)
+			}
+			elem, err := q.codec.Decode(payload)
+			if err != nil {
+				return fmt.Errorf("diskqueue: decode record %d: %w", seq, err)
+			}
+			q.window.PushBack(elem)
+			if next++; next >= end {
+				return errStop
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStop) {
+			q.window.Clear()
+			q.readSeg, q.readOff = readSeg, readOff
+			return err
+		}
+		q.readSeg, q.readOff = s.id, off
+	}
+	if next < end {
+		q.window.Clear()
+		q.readSeg, q.readOff = readSeg, readOff
+		return fmt.Errorf("%w: missing record %d", ErrCorrupt, next)
+	}
+	return nil
+}
+
+// listSegments 返回目录中所有日志段的 id，按升序排列。
+func (q *DiskQueue[T]) listSegments() ([]uint64, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// readSegment 从 offset 开始依次读取日志段中的记录并调用 fn，返回最后一条传给 fn 的记录之后的偏移量。
+// 如果 truncateTail 为 true，末尾残缺或校验失败的记录会被截断而不是报错。
+func readSegment(path string, offset int64, truncateTail bool, fn func(kind byte, seq uint64, payload []byte) error) (int64, error) {
+	flag := os.O_RDONLY
+	if truncateTail {
+		flag = os.O_RDWR
+	}
+	f, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return offset, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+
+	r := bufio.NewReader(f)
+	header := make([]byte, headerSize)
+	var body []byte
+	for {
+		bad, err := func() (string, error) {
+			if _, err := io.ReadFull(r, header); err != nil {
+				if errors.Is(err, io.ErrUnexpectedEOF) {
+					return "truncated header", nil
+				}
+				return "", err
+			}
+			size := binary.LittleEndian.Uint32(header[0:])
+			if size < bodyPrefixSize || size > maxRecordSize {
+				return fmt.Sprintf("invalid record length %d", size), nil
+			}
+			body = slices.Grow(body[:0], int(size))[:size]
+			if _, err := io.ReadFull(r, body); err != nil {
+				if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+					return "truncated record", nil
+				}
+				return "", err
+			}
+			if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(header[4:]) {
+				return "checksum mismatch", nil
+			}
+			return "", nil
+		}()
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+		if bad != "" {
+			if !truncateTail {
+				return offset, fmt.Errorf("%w: %s at offset %d in %s", ErrCorrupt, bad, offset, path)
+			}
+			return offset, f.Truncate(offset)
+		}
+
+		err = fn(body[0], binary.LittleEndian.Uint64(body[1:]), body[bodyPrefixSize:])
+		offset += headerSize + int64(len(body))
+		if err != nil {
+			return offset, err
+		}
+	}
+}
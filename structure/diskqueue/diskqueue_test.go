@@ -0,0 +1,353 @@
+package diskqueue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Repeater11/go-template/structure/codec"
+)
+
+func openQueue(t *testing.T, dir string, opts Options) *DiskQueue[string] {
+	t.Helper()
+	q, err := NewDiskQueue[string](dir, codec.String{}, opts)
+	if err != nil {
+		t.Fatalf("NewDiskQueue failed: %v", err)
+	}
+	return q
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestPushPop(t *testing.T) {
+	q := openQueue(t, t.TempDir(), Options{})
+	defer q.Close()
+
+	if _, err := q.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("Pop on empty queue should return ErrEmpty, got %v", err)
+	}
+	for _, s := range []string{"a", "b", "c"} {
+		if err := q.Push(s); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	if front, err := q.Front(); err != nil || front != "a" {
+		t.Fatalf("expected front a, got %q (%v)", front, err)
+	}
+	for _, want := range []string{"a", "b", "c"} {
+		got, err := q.Pop()
+		if err != nil || got != want {
+			t.Fatalf("expected %q, got %q (%v)", want, got, err)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Fatal("queue should be empty")
+	}
+	if _, err := q.Front(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("Front on empty queue should return ErrEmpty, got %v", err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{Sync: SyncAlways})
+	for _, s := range []string{"a", "b", "c", "d"} {
+		q.Push(s)
+	}
+	q.Pop()
+	q.Pop()
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := q.Push("x"); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Push after Close should return ErrClosed, got %v", err)
+	}
+
+	q = openQueue(t, dir, Options{})
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 elements after recovery, got %d", q.Len())
+	}
+	q.Push("e")
+	q.Close()
+
+	q = openQueue(t, dir, Options{})
+	defer q.Close()
+	for _, want := range []string{"c", "d", "e"} {
+		got, err := q.Pop()
+		if err != nil || got != want {
+			t.Fatalf("expected %q, got %q (%v)", want, got, err)
+		}
+	}
+}
+
+func TestTruncateTornTail(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{})
+	q.Push("a")
+	q.Push("b")
+	q.Close()
+
+	// 模拟崩溃时写了一半的记录
+	files := segmentFiles(t, dir)
+	f, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{20, 0, 0, 0, 1, 2, 3, 4, recordPush})
+	f.Close()
+
+	q = openQueue(t, dir, Options{})
+	if q.Len() != 2 {
+		t.Fatalf("expected 2 elements after truncation, got %d", q.Len())
+	}
+	q.Push("c")
+	q.Close()
+
+	q = openQueue(t, dir, Options{})
+	defer q.Close()
+	if q.Len() != 3 {
+		t.Fatalf("records after the truncated tail should survive, got len %d", q.Len())
+	}
+}
+
+func TestCorruptChecksum(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{})
+	q.Push("a")
+	q.Push("b")
+	q.Close()
+
+	// 破坏第二条记录的负载
+	files := segmentFiles(t, dir)
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(files[0], data, 0o644)
+
+	q = openQueue(t, dir, Options{})
+	defer q.Close()
+	if got, _ := q.Pop(); q.Len() != 0 || got != "a" {
+		t.Fatalf("corrupt tail record should be dropped, got %q with len %d", got, q.Len())
+	}
+}
+
+func TestCorruptEarlierSegment(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{SegmentSize: 1})
+	q.Push("a")
+	q.Push("b")
+	q.Close()
+
+	files := segmentFiles(t, dir)
+	if len(files) < 2 {
+		t.Fatalf("expected multiple segments, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(files[0], data, 0o644)
+
+	if _, err := NewDiskQueue[string](dir, codec.String{}, Options{}); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{SegmentSize: 64})
+	for i := 0; i < 20; i++ {
+		q.Push("element")
+	}
+	before := len(segmentFiles(t, dir))
+	if before < 3 {
+		t.Fatalf("expected several segments, got %d", before)
+	}
+	for i := 0; i < 15; i++ {
+		q.Pop()
+	}
+	after := len(segmentFiles(t, dir))
+	if after >= before {
+		t.Fatalf("consumed segments should be deleted, had %d, now %d", before, after)
+	}
+	q.Close()
+
+	q = openQueue(t, dir, Options{SegmentSize: 64})
+	defer q.Close()
+	if q.Len() != 5 {
+		t.Fatalf("expected 5 elements after compaction and recovery, got %d", q.Len())
+	}
+	for i := 0; i < 5; i++ {
+		q.Pop()
+	}
+	if n := len(segmentFiles(t, dir)); n != 1 {
+		t.Fatalf("only the active segment should remain, got %d", n)
+	}
+}
+
+func TestSyncBatch(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{Sync: SyncBatch, SyncEvery: 3})
+	for i := 0; i < 10; i++ {
+		if err := q.Push("x"); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	if err := q.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	q.Close()
+	if q.Sync() != ErrClosed {
+		t.Fatal("Sync after Close should return ErrClosed")
+	}
+
+	q = openQueue(t, dir, Options{})
+	defer q.Close()
+	if q.Len() != 10 {
+		t.Fatalf("expected 10 elements, got %d", q.Len())
+	}
+}
+
+func TestWindowBounded(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{SegmentSize: 256, WindowSize: 4}
+	q := openQueue(t, dir, opts)
+	for i := 0; i < 50; i++ {
+		if err := q.Push(strconv.Itoa(i)); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if q.window.Len() > opts.WindowSize {
+			t.Fatalf("window should hold at most %d elements, got %d", opts.WindowSize, q.window.Len())
+		}
+	}
+	if q.Len() != 50 {
+		t.Fatalf("expected 50 elements, got %d", q.Len())
+	}
+	for i := 0; i < 20; i++ {
+		got, err := q.Pop()
+		if err != nil || got != strconv.Itoa(i) {
+			t.Fatalf("expected %d, got %q (%v)", i, got, err)
+		}
+		if q.window.Len() > opts.WindowSize {
+			t.Fatalf("window should hold at most %d elements, got %d", opts.WindowSize, q.window.Len())
+		}
+	}
+	q.Close()
+
+	q = openQueue(t, dir, opts)
+	defer q.Close()
+	if q.window.Len() > opts.WindowSize {
+		t.Fatalf("recovered window should hold at most %d elements, got %d", opts.WindowSize, q.window.Len())
+	}
+	for i := 20; i < 50; i++ {
+		got, err := q.Pop()
+		if err != nil || got != strconv.Itoa(i) {
+			t.Fatalf("expected %d, got %q (%v)", i, got, err)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Fatal("queue should be empty")
+	}
+}
+
+func TestWindowInterleaved(t *testing.T) {
+	q := openQueue(t, t.TempDir(), Options{SegmentSize: 128, WindowSize: 2})
+	defer q.Close()
+	next, want := 0, 0
+	for round := 0; round < 20; round++ {
+		// 交替地批量入队和出队，使窗口在内存和日志之间反复补充
+		for i := 0; i < round%5+1; i++ {
+			q.Push(strconv.Itoa(next))
+			next++
+		}
+		for i := 0; i < round%3+1 && want < next; i++ {
+			got, err := q.Pop()
+			if err != nil || got != strconv.Itoa(want) {
+				t.Fatalf("expected %d, got %q (%v)", want, got, err)
+			}
+			want++
+		}
+	}
+	if q.Len() != next-want {
+		t.Fatalf("expected %d elements, got %d", next-want, q.Len())
+	}
+}
+
+func TestCompactionErrorDoesNotFailPop(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{SegmentSize: 64})
+	defer q.Close()
+	for i := 0; i < 10; i++ {
+		q.Push("element")
+	}
+	// 让删除旧日志段失败
+	files := segmentFiles(t, dir)
+	os.Remove(files[0])
+	os.Mkdir(files[0], 0o755)
+	os.WriteFile(filepath.Join(files[0], "keep"), nil, 0o644)
+
+	for i := 0; i < 5; i++ {
+		if got, err := q.Pop(); err != nil || got != "element" {
+			t.Fatalf("Pop should succeed once the pop record is written, got %q (%v)", got, err)
+		}
+	}
+	if q.Err() == nil {
+		t.Fatal("compaction failure should be reported by Err")
+	}
+}
+
+func TestWriteFailure(t *testing.T) {
+	dir := t.TempDir()
+	q := openQueue(t, dir, Options{})
+	q.Push("a")
+	q.active.Close() // 模拟底层文件不可写
+
+	if err := q.Push("b"); err == nil {
+		t.Fatal("Push should fail when the log cannot be written")
+	}
+	if err := q.Push("c"); err == nil {
+		t.Fatal("queue should stay failed after an unrecoverable write error")
+	}
+	if q.Len() != 1 {
+		t.Fatalf("failed Push should not change the queue, got len %d", q.Len())
+	}
+	q.Close()
+
+	q = openQueue(t, dir, Options{})
+	defer q.Close()
+	if got, err := q.Pop(); err != nil || got != "a" || !q.IsEmpty() {
+		t.Fatalf("expected only a after reopening, got %q (%v) with len %d", got, err, q.Len())
+	}
+}
+
+func BenchmarkDrainSegment(b *testing.B) {
+	const n, window = 1 << 14, 64
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		q, err := NewDiskQueue[string](b.TempDir(), codec.String{}, Options{WindowSize: window})
+		if err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < n; j++ {
+			q.Push("element")
+		}
+		b.StartTimer()
+		// 所有元素都在同一个日志段中，需要补充 n/window 次窗口
+		for j := 0; j < n; j++ {
+			if _, err := q.Pop(); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.StopTimer()
+		q.Close()
+	}
+}
@@ -0,0 +1,222 @@
+// Package flatmap 提供了基于有序 Vector 的泛型映射实现，接口风格贴近 boost::container::flat_map。
+package flatmap
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/Repeater11/go-template/structure/vector"
+)
+
+// entry 是 FlatMap 中的一个键值对。
+type entry[K, V any] struct {
+	key   K
+	value V
+}
+
+// FlatMap 是一个按键有序的映射，键值对连续保存在 Vector 中。
+// 查找通过二分完成，时间复杂度为 O(log n)，并且有良好的缓存局部性；
+// 单个键的插入和删除需要移动元素，时间复杂度为 O(n)，适合读多写少的场景。
+// 批量插入时只排序一次，应优先使用 PutAll。
+type FlatMap[K, V any] struct {
+	data *vector.Vector[entry[K, V]]
+	cmp  func(a, b K) int
+}
+
+// NewFlatMap 使用自定义键比较函数创建一个空的 FlatMap。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+func NewFlatMap[K, V any](cmp func(a, b K) int) *FlatMap[K, V] {
+	return &FlatMap[K, V]{
+		data: vector.NewVector[entry[K, V]](),
+		cmp:  cmp,
+	}
+}
+
+// NewOrderedFlatMap 创建一个按键升序排列的空 FlatMap。
+// 仅适用于键类型实现了 cmp.Ordered 接口的情况（如 int, float64, string 等）。
+func NewOrderedFlatMap[K cmp.Ordered, V any]() *FlatMap[K, V] {
+	return NewFlatMap[K, V](cmp.Compare[K])
+}
+
+// Len 返回 FlatMap 中键值对的数量。
+func (m *FlatMap[K, V]) Len() int {
+	return m.data.Len()
+}
+
+// IsEmpty 检查 FlatMap 是否为空。
+func (m *FlatMap[K, V]) IsEmpty() bool {
+	return m.data.IsEmpty()
+}
+
+// Clear 移除 FlatMap 中的所有键值对。
+func (m *FlatMap[K, V]) Clear() {
+	m.data.Clear()
+}
+
+// At 返回第 index 小的键及其值，不检查边界。
+func (m *FlatMap[K, V]) At(index int) (K, V) {
+	e := m.data.At(index)
+	return e.key, e.value
+}
+
+// LowerBound 返回第一个不小于 key 的键的索引，不存在时返回 Len()。
+func (m *FlatMap[K, V]) LowerBound(key K) int {
+	return m.data.LowerBoundFunc(entry[K, V]{key: key}, m.compare)
+}
+
+// UpperBound 返回第一个大于 key 的键的索引，不存在时返回 Len()。
+func (m *FlatMap[K, V]) UpperBound(key K) int {
+	return m.data.UpperBoundFunc(entry[K, V]{key: key}, m.compare)
+}
+
+// Find 返回键的索引。
+// 如果键不存在，返回它应当插入的位置和 false。
+func (m *FlatMap[K, V]) Find(key K) (int, bool) {
+	return m.data.BinarySearchFunc(entry[K, V]{key: key}, m.compare)
+}
+
+// Get 返回键对应的值。
+// 如果键不存在，返回零值和 false。
+func (m *FlatMap[K, V]) Get(key K) (V, bool) {
+	i, ok := m.Find(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return m.data.At(i).value, true
+}
+
+// Contains 检查键是否存在。
+func (m *FlatMap[K, V]) Contains(key K) bool {
+	_, ok := m.Find(key)
+	return ok
+}
+
+// Put 设置键对应的值并保持有序，键已存在时覆盖原值。
+func (m *FlatMap[K, V]) Put(key K, value V) {
+	i, ok := m.Find(key)
+	if ok {
+		m.data.Set(i, entry[K, V]{key, value})
+		return
+	}
+	m.data.Insert(i, entry[K, V]{key, value})
+}
+
+// PutAll 批量设置无序的键值对，只进行一次排序和去重。
+// 同一个键出现多次时，以最后一次出现的值为准，并覆盖已有的值。
+func (m *FlatMap[K, V]) PutAll(seq iter.Seq2[K, V]) {
+	merged := m.data.ToSlice()
+	existing := len(merged)
+	for k, v := range seq {
+		merged = append(merged, entry[K, V]{k, v})
+	}
+	if len(merged) == existing {
+		return
+	}
+	// 稳定排序后相同键的键值对保持插入顺序，反转后去重即可保留最后一个
+	slices.Reverse(merged)
+	slices.SortStableFunc(merged, m.compare)
+	merged = slices.CompactFunc(merged, func(a, b entry[K, V]) bool { return m.compare(a, b) == 0 })
+	m.data = vector.NewVectorFromSlice(merged)
+}
+
+// Delete 删除键，如果键不存在返回 false。
+func (m *FlatMap[K, V]) Delete(key K) bool {
+	i, ok := m.Find(key)
+	if !ok {
+		return false
+	}
+	m.data.Erase(i, i+1)
+	return true
+}
+
+// DeleteRange 删除键在 [lo, hi) 范围内的所有键值对，返回删除的数量。
+func (m *FlatMap[K, V]) DeleteRange(lo, hi K) int {
+	begin, end := m.LowerBound(lo), m.LowerBound(hi)
+	if begin >= end {
+		return 0
+	}
+	m.data.Erase(begin, end)
+	return end - begin
+}
+
+// Min 返回最小的键及其值。
+// 如果 FlatMap 为空，返回零值和 false。
+func (m *FlatMap[K, V]) Min() (K, V, bool) {
+	e, ok := m.data.Front()
+	return e.key, e.value, ok
+}
+
+// Max 返回最大的键及其值。
+// 如果 FlatMap 为空，返回零值和 false。
+func (m *FlatMap[K, V]) Max() (K, V, bool) {
+	e, ok := m.data.Back()
+	return e.key, e.value, ok
+}
+
+// All 返回一个按键升序遍历所有键值对的迭代器。
+func (m *FlatMap[K, V]) All() iter.Seq2[K, V] {
+	return m.between(0, m.data.Len())
+}
+
+// Backward 返回一个按键降序遍历所有键值对的迭代器。
+func (m *FlatMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := m.data.Len() - 1; i >= 0; i-- {
+			e := m.data.At(i)
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range 返回一个按键升序遍历键在 [lo, hi) 范围内的键值对的迭代器。
+func (m *FlatMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return m.between(m.LowerBound(lo), m.LowerBound(hi))
+}
+
+// Keys 返回一个按升序遍历所有键的迭代器。
+func (m *FlatMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values 返回一个按键的升序遍历所有值的迭代器。
+func (m *FlatMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Clone 创建并返回 FlatMap 的一个副本。
+func (m *FlatMap[K, V]) Clone() *FlatMap[K, V] {
+	return &FlatMap[K, V]{data: m.data.Clone(), cmp: m.cmp}
+}
+
+// compare 按键比较两个键值对。
+func (m *FlatMap[K, V]) compare(a, b entry[K, V]) int {
+	return m.cmp(a.key, b.key)
+}
+
+// between 返回一个遍历索引 [begin, end) 内键值对的迭代器。
+func (m *FlatMap[K, V]) between(begin, end int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := begin; i < end && i < m.data.Len(); i++ {
+			e := m.data.At(i)
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
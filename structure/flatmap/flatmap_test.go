@@ -0,0 +1,138 @@
+package flatmap
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestPutGet(t *testing.T) {
+	m := NewOrderedFlatMap[string, int]()
+	m.Put("b", 2)
+	m.Put("a", 1)
+	m.Put("c", 3)
+	m.Put("b", 20)
+
+	if v, ok := m.Get("b"); !ok || v != 20 {
+		t.Fatalf("expected b=20, got %d", v)
+	}
+	if _, ok := m.Get("z"); ok {
+		t.Fatal("Get of missing key should fail")
+	}
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatalf("keys should be sorted, got %v", got)
+	}
+	if k, v := m.At(0); k != "a" || v != 1 {
+		t.Fatalf("expected At(0) = a 1, got %s %d", k, v)
+	}
+	if k, _, _ := m.Min(); k != "a" {
+		t.Fatalf("expected min a, got %s", k)
+	}
+	if k, _, _ := m.Max(); k != "c" {
+		t.Fatalf("expected max c, got %s", k)
+	}
+}
+
+func TestPutAll(t *testing.T) {
+	m := NewOrderedFlatMap[int, string]()
+	m.Put(1, "old")
+	m.Put(5, "keep")
+
+	input := func(yield func(int, string) bool) {
+		for _, kv := range []struct {
+			k int
+			v string
+		}{{3, "x"}, {1, "new"}, {3, "y"}, {2, "z"}} {
+			if !yield(kv.k, kv.v) {
+				return
+			}
+		}
+	}
+	m.PutAll(input)
+
+	want := map[int]string{1: "new", 2: "z", 3: "y", 5: "keep"}
+	if got := maps.Collect(m.All()); !maps.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []int{1, 2, 3, 5}) {
+		t.Fatalf("keys should be sorted, got %v", got)
+	}
+
+	m.PutAll(maps.All(map[int]string{}))
+	if m.Len() != 4 {
+		t.Fatal("PutAll with empty input should be a no-op")
+	}
+}
+
+func TestBoundsAndFind(t *testing.T) {
+	m := NewOrderedFlatMap[int, int]()
+	m.PutAll(maps.All(map[int]int{10: 1, 20: 2, 30: 3}))
+	if m.LowerBound(20) != 1 || m.UpperBound(20) != 2 {
+		t.Fatal("unexpected bounds for existing key")
+	}
+	if m.LowerBound(25) != 2 || m.UpperBound(25) != 2 {
+		t.Fatal("unexpected bounds for missing key")
+	}
+	if i, ok := m.Find(30); !ok || i != 2 {
+		t.Fatalf("Find(30) expected (2,true), got (%d,%v)", i, ok)
+	}
+	if i, ok := m.Find(0); ok || i != 0 {
+		t.Fatalf("Find(0) expected (0,false), got (%d,%v)", i, ok)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	m := NewOrderedFlatMap[int, int]()
+	for i := 0; i < 10; i++ {
+		m.Put(i, i*i)
+	}
+	if !m.Delete(3) || m.Delete(3) || m.Contains(3) {
+		t.Fatal("Delete should succeed once")
+	}
+	if n := m.DeleteRange(5, 8); n != 3 {
+		t.Fatalf("expected 3 deleted, got %d", n)
+	}
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []int{0, 1, 2, 4, 8, 9}) {
+		t.Fatalf("unexpected keys %v", got)
+	}
+	m.Clear()
+	if !m.IsEmpty() {
+		t.Fatal("map should be empty after Clear")
+	}
+}
+
+func TestIteration(t *testing.T) {
+	m := NewOrderedFlatMap[int, string]()
+	m.PutAll(maps.All(map[int]string{1: "a", 2: "b", 3: "c", 4: "d"}))
+
+	var keys []int
+	for k, v := range m.Range(2, 4) {
+		keys = append(keys, k)
+		if v != string(rune('a'+k-1)) {
+			t.Fatalf("unexpected value %q for key %d", v, k)
+		}
+	}
+	if !slices.Equal(keys, []int{2, 3}) {
+		t.Fatalf("unexpected range keys %v", keys)
+	}
+	var back []int
+	for k := range m.Backward() {
+		back = append(back, k)
+	}
+	if !slices.Equal(back, []int{4, 3, 2, 1}) {
+		t.Fatalf("unexpected backward keys %v", back)
+	}
+	if got := slices.Collect(m.Values()); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("unexpected values %v", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	m := NewOrderedFlatMap[int, int]()
+	m.Put(1, 1)
+	c := m.Clone()
+	c.Put(1, 2)
+	if v, _ := m.Get(1); v != 1 {
+		t.Fatal("clone should be independent")
+	}
+}
@@ -0,0 +1,177 @@
+// Package flatset 提供了基于有序 Vector 的泛型集合实现，接口风格贴近 boost::container::flat_set。
+package flatset
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/Repeater11/go-template/structure/vector"
+)
+
+// FlatSet 是一个按比较函数有序且不重复的集合，元素连续保存在 Vector 中。
+// 查找通过二分完成，时间复杂度为 O(log n)，并且有良好的缓存局部性；
+// 单个元素的插入和删除需要移动元素，时间复杂度为 O(n)，适合读多写少的场景。
+// 批量插入时只排序一次，应优先使用 NewFlatSet 或 InsertAll。
+type FlatSet[T any] struct {
+	data *vector.Vector[T]
+	cmp  func(a, b T) int
+}
+
+// NewFlatSet 使用自定义比较函数创建一个 FlatSet。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+// 可选地，可以传入无序且可能重复的初始元素，重复元素只保留第一个。
+func NewFlatSet[T any](cmp func(a, b T) int, elements ...T) *FlatSet[T] {
+	s := &FlatSet[T]{
+		data: vector.NewVector[T](),
+		cmp:  cmp,
+	}
+	s.InsertAll(elements...)
+	return s
+}
+
+// NewOrderedFlatSet 创建一个按升序排列的 FlatSet。
+// 仅适用于实现了 cmp.Ordered 接口的类型（如 int, float64, string 等）。
+func NewOrderedFlatSet[T cmp.Ordered](elements ...T) *FlatSet[T] {
+	return NewFlatSet(cmp.Compare[T], elements...)
+}
+
+// Len 返回 FlatSet 中元素的数量。
+func (s *FlatSet[T]) Len() int {
+	return s.data.Len()
+}
+
+// IsEmpty 检查 FlatSet 是否为空。
+func (s *FlatSet[T]) IsEmpty() bool {
+	return s.data.IsEmpty()
+}
+
+// Clear 移除 FlatSet 中的所有元素。
+func (s *FlatSet[T]) Clear() {
+	s.data.Clear()
+}
+
+// At 返回第 index 小的元素，不检查边界。
+func (s *FlatSet[T]) At(index int) T {
+	return s.data.At(index)
+}
+
+// LowerBound 返回第一个不小于 x 的元素的索引，不存在时返回 Len()。
+func (s *FlatSet[T]) LowerBound(x T) int {
+	return s.data.LowerBoundFunc(x, s.cmp)
+}
+
+// UpperBound 返回第一个大于 x 的元素的索引，不存在时返回 Len()。
+func (s *FlatSet[T]) UpperBound(x T) int {
+	return s.data.UpperBoundFunc(x, s.cmp)
+}
+
+// Find 返回与 x 相等的元素的索引。
+// 如果不存在，返回 x 应当插入的位置和 false。
+func (s *FlatSet[T]) Find(x T) (int, bool) {
+	return s.data.BinarySearchFunc(x, s.cmp)
+}
+
+// Contains 检查 FlatSet 是否包含指定的元素。
+func (s *FlatSet[T]) Contains(x T) bool {
+	_, ok := s.Find(x)
+	return ok
+}
+
+// Insert 插入一个元素并保持有序。
+// 如果元素已存在，返回 false。
+func (s *FlatSet[T]) Insert(x T) bool {
+	i, ok := s.Find(x)
+	if ok {
+		return false
+	}
+	s.data.Insert(i, x)
+	return true
+}
+
+// InsertAll 批量插入元素，只进行一次排序和去重，已存在的元素会被忽略。
+func (s *FlatSet[T]) InsertAll(elements ...T) {
+	if len(elements) == 0 {
+		return
+	}
+	// 已有元素排在前面，稳定排序后去重时会优先保留它们
+	merged := append(s.data.ToSlice(), elements...)
+	slices.SortStableFunc(merged, s.cmp)
+	merged = slices.CompactFunc(merged, func(a, b T) bool { return s.cmp(a, b) == 0 })
+	s.data = vector.NewVectorFromSlice(merged)
+}
+
+// Erase 删除与 x 相等的元素。
+// 如果元素不存在，返回 false。
+func (s *FlatSet[T]) Erase(x T) bool {
+	i, ok := s.Find(x)
+	if !ok {
+		return false
+	}
+	s.data.Erase(i, i+1)
+	return true
+}
+
+// EraseRange 删除 [lo, hi) 范围内的所有元素，返回删除的数量。
+func (s *FlatSet[T]) EraseRange(lo, hi T) int {
+	begin, end := s.LowerBound(lo), s.LowerBound(hi)
+	if begin >= end {
+		return 0
+	}
+	s.data.Erase(begin, end)
+	return end - begin
+}
+
+// First 返回最小的元素。
+// 如果 FlatSet 为空，返回零值和 false。
+func (s *FlatSet[T]) First() (T, bool) {
+	return s.data.Front()
+}
+
+// Last 返回最大的元素。
+// 如果 FlatSet 为空，返回零值和 false。
+func (s *FlatSet[T]) Last() (T, bool) {
+	return s.data.Back()
+}
+
+// All 返回一个按升序遍历所有元素的迭代器。
+func (s *FlatSet[T]) All() iter.Seq[T] {
+	return s.between(0, s.data.Len())
+}
+
+// Backward 返回一个按降序遍历所有元素的迭代器。
+func (s *FlatSet[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.data.Len() - 1; i >= 0; i-- {
+			if !yield(s.data.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Range 返回一个按升序遍历 [lo, hi) 范围内元素的迭代器。
+func (s *FlatSet[T]) Range(lo, hi T) iter.Seq[T] {
+	return s.between(s.LowerBound(lo), s.LowerBound(hi))
+}
+
+// ToSlice 返回按升序排列的所有元素。
+func (s *FlatSet[T]) ToSlice() []T {
+	return s.data.ToSlice()
+}
+
+// Clone 创建并返回 FlatSet 的一个副本。
+func (s *FlatSet[T]) Clone() *FlatSet[T] {
+	return &FlatSet[T]{data: s.data.Clone(), cmp: s.cmp}
+}
+
+// between 返回一个遍历索引 [begin, end) 内元素的迭代器。
+func (s *FlatSet[T]) between(begin, end int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := begin; i < end && i < s.data.Len(); i++ {
+			if !yield(s.data.At(i)) {
+				return
+			}
+		}
+	}
+}
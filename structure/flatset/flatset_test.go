@@ -0,0 +1,116 @@
+package flatset
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBulkBuild(t *testing.T) {
+	s := NewOrderedFlatSet(5, 3, 9, 3, 1, 5, 7)
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 3, 5, 7, 9}) {
+		t.Fatalf("expected sorted unique elements, got %v", got)
+	}
+	if first, _ := s.First(); first != 1 {
+		t.Fatalf("expected first 1, got %d", first)
+	}
+	if last, _ := s.Last(); last != 9 {
+		t.Fatalf("expected last 9, got %d", last)
+	}
+	if s.At(2) != 5 {
+		t.Fatalf("expected At(2) 5, got %d", s.At(2))
+	}
+}
+
+func TestBulkBuildKeepsFirst(t *testing.T) {
+	fold := func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) }
+	s := NewFlatSet(fold, "b", "A", "a", "B")
+	if got := s.ToSlice(); !slices.Equal(got, []string{"A", "b"}) {
+		t.Fatalf("duplicates should keep the first occurrence, got %v", got)
+	}
+	s.InsertAll("a", "c")
+	if got := s.ToSlice(); !slices.Equal(got, []string{"A", "b", "c"}) {
+		t.Fatalf("InsertAll should keep existing elements, got %v", got)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	s := NewOrderedFlatSet(10, 20, 30)
+	cases := []struct{ x, lower, upper int }{
+		{5, 0, 0},
+		{10, 0, 1},
+		{15, 1, 1},
+		{30, 2, 3},
+		{35, 3, 3},
+	}
+	for _, c := range cases {
+		if got := s.LowerBound(c.x); got != c.lower {
+			t.Fatalf("LowerBound(%d) expected %d, got %d", c.x, c.lower, got)
+		}
+		if got := s.UpperBound(c.x); got != c.upper {
+			t.Fatalf("UpperBound(%d) expected %d, got %d", c.x, c.upper, got)
+		}
+	}
+	if i, ok := s.Find(20); !ok || i != 1 {
+		t.Fatalf("Find(20) expected (1,true), got (%d,%v)", i, ok)
+	}
+	if i, ok := s.Find(25); ok || i != 2 {
+		t.Fatalf("Find(25) expected (2,false), got (%d,%v)", i, ok)
+	}
+}
+
+func TestInsertErase(t *testing.T) {
+	s := NewOrderedFlatSet[int]()
+	for _, x := range []int{4, 1, 3, 1, 2} {
+		s.Insert(x)
+	}
+	if s.Insert(3) {
+		t.Fatal("Insert of existing element should return false")
+	}
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("unexpected elements %v", got)
+	}
+	if !s.Erase(2) || s.Erase(2) {
+		t.Fatal("Erase should succeed once")
+	}
+	if !s.Contains(3) || s.Contains(2) {
+		t.Fatal("unexpected Contains results")
+	}
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Fatal("set should be empty after Clear")
+	}
+}
+
+func TestRangeIteration(t *testing.T) {
+	s := NewOrderedFlatSet(1, 2, 3, 4, 5, 6)
+	if got := slices.Collect(s.Range(2, 5)); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Fatalf("unexpected range %v", got)
+	}
+	if got := slices.Collect(s.Range(5, 2)); len(got) != 0 {
+		t.Fatalf("inverted range should be empty, got %v", got)
+	}
+	if got := slices.Collect(s.Backward()); !slices.Equal(got, []int{6, 5, 4, 3, 2, 1}) {
+		t.Fatalf("unexpected backward order %v", got)
+	}
+	for x := range s.All() {
+		if x == 3 {
+			break
+		}
+	}
+	if n := s.EraseRange(2, 5); n != 3 || s.Len() != 3 {
+		t.Fatalf("EraseRange should remove 3 elements, removed %d, len %d", n, s.Len())
+	}
+	if n := s.EraseRange(10, 20); n != 0 {
+		t.Fatalf("EraseRange outside elements should remove nothing, removed %d", n)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := NewOrderedFlatSet(1, 2)
+	c := s.Clone()
+	c.Insert(3)
+	if s.Len() != 2 || c.Len() != 3 {
+		t.Fatal("clone should be independent")
+	}
+}
@@ -0,0 +1,243 @@
+// Package history 提供了基于栈的撤销/重做历史管理，支持事务分组、最大深度和保存点。
+package history
+
+import (
+	"errors"
+
+	"github.com/Repeater11/go-template/structure/stack"
+)
+
+var (
+	// ErrNothingToUndo 表示没有可以撤销的操作。
+	ErrNothingToUndo = errors.New("history: nothing to undo")
+	// ErrNothingToRedo 表示没有可以重做的操作。
+	ErrNothingToRedo = errors.New("history: nothing to redo")
+	// ErrInTransaction 表示事务进行中，不能撤销或重做。
+	ErrInTransaction = errors.New("history: transaction in progress")
+	// ErrNoTransaction 表示当前没有进行中的事务。
+	ErrNoTransaction = errors.New("history: no transaction in progress")
+)
+
+// Command 是一个可以撤销的操作。
+type Command interface {
+	// Do 执行操作，重做时也会被调用。
+	Do() error
+	// Undo 撤销 Do 的效果。
+	Undo() error
+}
+
+// Group 是按顺序执行的一组命令，撤销时按相反顺序撤销。
+// 中途失败时会回滚已完成的部分，使状态保持不变。
+type Group []Command
+
+// Do 依次执行所有命令，某个命令失败时撤销之前已执行的命令并返回错误。
+func (g Group) Do() error {
+	for i, c := range g {
+		if err := c.Do(); err != nil {
+			return errors.Join(err, g[:i].undo())
+		}
+	}
+	return nil
+}
+
+// Undo 按相反顺序撤销所有命令，某个命令失败时重新执行已撤销的命令并返回错误。
+func (g Group) Undo() error {
+	return g.undo()
+}
+
+// undo 按相反顺序撤销所有命令。
+func (g Group) undo() error {
+	for i := len(g) - 1; i >= 0; i-- {
+		if err := g[i].Undo(); err != nil {
+			for _, c := range g[i+1:] {
+				err = errors.Join(err, c.Do())
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// entry 是历史中的一项，id 唯一标识执行该项之后的状态。
+type entry struct {
+	cmd Command
+	id  uint64
+}
+
+// History 管理命令的撤销与重做。
+// 新的记录进入撤销历史时会清空重做历史；撤销历史超过最大深度时丢弃最早的记录。
+// 事务中执行的命令在提交时合并为一个 Group，作为一个整体撤销和重做；事务可以嵌套。
+// History 不是并发安全的。
+type History struct {
+	undo     *stack.Stack[entry]
+	redo     *stack.Stack[entry]
+	maxDepth int
+	txns     *stack.Stack[Group] // 进行中的事务，栈顶是最内层
+	nextID   uint64
+	baseID   uint64 // 撤销历史为空时的状态 id
+	savedID  uint64 // 保存点的状态 id
+}
+
+// NewHistory 创建一个空的历史，maxDepth 为最多保留的撤销记录数，不为正数时不限制。
+func NewHistory(maxDepth int) *History {
+	return &History{
+		undo:     stack.NewStack[entry](),
+		redo:     stack.NewStack[entry](),
+		maxDepth: maxDepth,
+		txns:     stack.NewStack[Group](),
+	}
+}
+
+// Execute 执行一个命令并将其记录到历史中。
+// 如果命令执行失败，返回其错误且历史不变。
+// 在事务中执行的命令会在提交时才记录到撤销历史中，重做历史也在那时才清空。
+func (h *History) Execute(c Command) error {
+	if err := c.Do(); err != nil {
+		return err
+	}
+	if g, ok := h.txns.Pop(); ok {
+		h.txns.Push(append(g, c))
+		return nil
+	}
+	h.record(c)
+	return nil
+}
+
+// Undo 撤销最近一次执行或重做的命令。
+// 如果命令撤销失败，返回其错误且命令保留在撤销历史中。
+func (h *History) Undo() error {
+	if h.InTransaction() {
+		return ErrInTransaction
+	}
+	e, ok := h.undo.Top()
+	if !ok {
+		return ErrNothingToUndo
+	}
+	if err := e.cmd.Undo(); err != nil {
+		return err
+	}
+	h.undo.Pop()
+	h.redo.Push(e)
+	return nil
+}
+
+// Redo 重新执行最近一次撤销的命令。
+// 如果命令执行失败，返回其错误且命令保留在重做历史中。
+func (h *History) Redo() error {
+	if h.InTransaction() {
+		return ErrInTransaction
+	}
+	e, ok := h.redo.Top()
+	if !ok {
+		return ErrNothingToRedo
+	}
+	if err := e.cmd.Do(); err != nil {
+		return err
+	}
+	h.redo.Pop()
+	h.undo.Push(e)
+	return nil
+}
+
+// CanUndo 检查是否有可以撤销的命令。
+func (h *History) CanUndo() bool {
+	return !h.InTransaction() && !h.undo.IsEmpty()
+}
+
+// CanRedo 检查是否有可以重做的命令。
+func (h *History) CanRedo() bool {
+	return !h.InTransaction() && !h.redo.IsEmpty()
+}
+
+// UndoLen 返回撤销历史中的记录数。
+func (h *History) UndoLen() int {
+	return h.undo.Len()
+}
+
+// RedoLen 返回重做历史中的记录数。
+func (h *History) RedoLen() int {
+	return h.redo.Len()
+}
+
+// Begin 开始一个事务，之后执行的命令会在 Commit 时合并为一条记录。
+// 事务可以嵌套，内层事务提交时并入外层事务。
+func (h *History) Begin() {
+	h.txns.Push(nil)
+}
+
+// InTransaction 检查是否有进行中的事务。
+func (h *History) InTransaction() bool {
+	return !h.txns.IsEmpty()
+}
+
+// Commit 提交最内层的事务。
+// 最外层事务提交时，其中的命令作为一个 Group 记录到撤销历史中，没有执行任何命令时不产生记录。
+func (h *History) Commit() error {
+	g, ok := h.txns.Pop()
+	if !ok {
+		return ErrNoTransaction
+	}
+	if len(g) == 0 {
+		return nil
+	}
+	if parent, ok := h.txns.Pop(); ok {
+		h.txns.Push(append(parent, g...))
+		return nil
+	}
+	h.record(g)
+	return nil
+}
+
+// Rollback 按相反顺序撤销最内层事务中执行的命令并结束该事务。
+// 如果某个命令撤销失败，已撤销的命令会被重新执行，事务保持进行中并返回错误。
+func (h *History) Rollback() error {
+	g, ok := h.txns.Top()
+	if !ok {
+		return ErrNoTransaction
+	}
+	if err := g.Undo(); err != nil {
+		return err
+	}
+	h.txns.Pop()
+	return nil
+}
+
+// MarkSaved 将当前状态标记为已保存。
+func (h *History) MarkSaved() {
+	h.savedID = h.currentID()
+}
+
+// IsDirty 检查当前状态是否与最近一次 MarkSaved 时不同。
+// 撤销或重做回到保存时的状态后，IsDirty 会重新返回 false；事务中总是返回 true。
+func (h *History) IsDirty() bool {
+	return h.InTransaction() || h.currentID() != h.savedID
+}
+
+// Clear 清空撤销和重做历史，当前状态被视为已保存。
+// 进行中的事务不受影响。
+func (h *History) Clear() {
+	h.undo.Clear()
+	h.redo.Clear()
+	h.nextID++
+	h.baseID = h.nextID
+	h.savedID = h.baseID
+}
+
+// record 将一条命令记录到撤销历史中并清空重做历史，超过最大深度时丢弃最早的记录。
+func (h *History) record(c Command) {
+	h.redo.Clear()
+	h.nextID++
+	h.undo.Push(entry{cmd: c, id: h.nextID})
+	for h.maxDepth > 0 && h.undo.Len() > h.maxDepth {
+		e, _ := h.undo.PopBottom()
+		h.baseID = e.id
+	}
+}
+
+// currentID 返回当前状态的 id。
+func (h *History) currentID() uint64 {
+	if e, ok := h.undo.Top(); ok {
+		return e.id
+	}
+	return h.baseID
+}
@@ -0,0 +1,292 @@
+package history
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// doc 是测试用的简单文档，按顺序保存文本片段。
+type doc struct {
+	parts []string
+}
+
+// appendCmd 在文档末尾追加一个片段。
+type appendCmd struct {
+	d    *doc
+	text string
+	fail bool // 为 true 时 Do 返回错误
+}
+
+func (c *appendCmd) Do() error {
+	if c.fail {
+		return errors.New("append failed")
+	}
+	c.d.parts = append(c.d.parts, c.text)
+	return nil
+}
+
+func (c *appendCmd) Undo() error {
+	c.d.parts = c.d.parts[:len(c.d.parts)-1]
+	return nil
+}
+
+// stuckCmd 的 Undo 总是失败。
+type stuckCmd struct{}
+
+func (stuckCmd) Do() error   { return nil }
+func (stuckCmd) Undo() error { return errors.New("cannot undo") }
+
+func expectParts(t *testing.T, d *doc, want ...string) {
+	t.Helper()
+	if !slices.Equal(d.parts, want) {
+		t.Fatalf("expected %v, got %v", want, d.parts)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(0)
+	if err := h.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo, got %v", err)
+	}
+
+	h.Execute(&appendCmd{d: d, text: "a"})
+	h.Execute(&appendCmd{d: d, text: "b"})
+	h.Execute(&appendCmd{d: d, text: "c"})
+	expectParts(t, d, "a", "b", "c")
+
+	h.Undo()
+	h.Undo()
+	expectParts(t, d, "a")
+	if !h.CanUndo() || !h.CanRedo() || h.RedoLen() != 2 {
+		t.Fatal("expected both undo and redo to be available")
+	}
+
+	h.Redo()
+	expectParts(t, d, "a", "b")
+
+	h.Execute(&appendCmd{d: d, text: "x"})
+	if h.CanRedo() {
+		t.Fatal("Execute should clear redo history")
+	}
+	if err := h.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Fatalf("expected ErrNothingToRedo, got %v", err)
+	}
+	expectParts(t, d, "a", "b", "x")
+}
+
+func TestExecuteFailure(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(0)
+	if err := h.Execute(&appendCmd{d: d, text: "a", fail: true}); err == nil {
+		t.Fatal("expected Execute to fail")
+	}
+	if h.CanUndo() {
+		t.Fatal("failed command should not be recorded")
+	}
+}
+
+func TestUndoFailureKeepsCommand(t *testing.T) {
+	h := NewHistory(0)
+	h.Execute(stuckCmd{})
+	if err := h.Undo(); err == nil {
+		t.Fatal("expected Undo to fail")
+	}
+	if h.UndoLen() != 1 || h.CanRedo() {
+		t.Fatal("failed undo should keep the command in undo history")
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(2)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		h.Execute(&appendCmd{d: d, text: s})
+	}
+	if h.UndoLen() != 2 {
+		t.Fatalf("expected 2 undo entries, got %d", h.UndoLen())
+	}
+	h.Undo()
+	h.Undo()
+	if err := h.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("oldest entries should be dropped, got %v", err)
+	}
+	expectParts(t, d, "a", "b")
+}
+
+func TestTransaction(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(0)
+	h.Execute(&appendCmd{d: d, text: "a"})
+
+	h.Begin()
+	h.Execute(&appendCmd{d: d, text: "b"})
+	h.Execute(&appendCmd{d: d, text: "c"})
+	if err := h.Undo(); !errors.Is(err, ErrInTransaction) {
+		t.Fatalf("expected ErrInTransaction, got %v", err)
+	}
+	if err := h.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if h.UndoLen() != 2 {
+		t.Fatalf("transaction should be recorded as one entry, got %d entries", h.UndoLen())
+	}
+
+	h.Undo()
+	expectParts(t, d, "a")
+	h.Redo()
+	expectParts(t, d, "a", "b", "c")
+
+	if err := h.Commit(); !errors.Is(err, ErrNoTransaction) {
+		t.Fatalf("expected ErrNoTransaction, got %v", err)
+	}
+	h.Begin()
+	h.Commit()
+	if h.UndoLen() != 2 {
+		t.Fatal("empty transaction should not be recorded")
+	}
+}
+
+func TestNestedTransactionRollback(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(0)
+
+	h.Begin()
+	h.Execute(&appendCmd{d: d, text: "a"})
+	h.Begin()
+	h.Execute(&appendCmd{d: d, text: "b"})
+	h.Execute(&appendCmd{d: d, text: "c"})
+	if err := h.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	expectParts(t, d, "a")
+
+	h.Begin()
+	h.Execute(&appendCmd{d: d, text: "d"})
+	h.Commit()
+	h.Commit()
+	expectParts(t, d, "a", "d")
+
+	if h.UndoLen() != 1 {
+		t.Fatalf("nested transactions should merge into one entry, got %d", h.UndoLen())
+	}
+	h.Undo()
+	expectParts(t, d)
+	if err := h.Rollback(); !errors.Is(err, ErrNoTransaction) {
+		t.Fatalf("expected ErrNoTransaction, got %v", err)
+	}
+}
+
+func TestRollbackKeepsRedo(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(0)
+	h.Execute(&appendCmd{d: d, text: "a"})
+	h.Undo()
+
+	h.Begin()
+	h.Execute(&appendCmd{d: d, text: "b"})
+	if err := h.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if err := h.Redo(); err != nil {
+		t.Fatalf("Redo after Rollback failed: %v", err)
+	}
+	expectParts(t, d, "a")
+
+	h.Undo()
+	h.Begin()
+	h.Begin()
+	h.Execute(&appendCmd{d: d, text: "c"})
+	h.Commit()
+	if h.RedoLen() != 1 {
+		t.Fatal("committing an inner transaction should keep redo history")
+	}
+	h.Commit()
+	if h.CanRedo() {
+		t.Fatal("committing the outermost transaction should clear redo history")
+	}
+	expectParts(t, d, "c")
+}
+
+func TestRollbackFailure(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(0)
+	h.Begin()
+	h.Execute(stuckCmd{})
+	h.Execute(&appendCmd{d: d, text: "a"})
+	if err := h.Rollback(); err == nil {
+		t.Fatal("expected Rollback to fail")
+	}
+	expectParts(t, d, "a")
+	if !h.InTransaction() {
+		t.Fatal("transaction should stay open after a failed rollback")
+	}
+}
+
+func TestGroupDoFailure(t *testing.T) {
+	d := &doc{}
+	g := Group{
+		&appendCmd{d: d, text: "a"},
+		&appendCmd{d: d, text: "b"},
+		&appendCmd{d: d, text: "c", fail: true},
+	}
+	if err := g.Do(); err == nil {
+		t.Fatal("expected Group.Do to fail")
+	}
+	expectParts(t, d)
+}
+
+func TestSavepoint(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(0)
+	if h.IsDirty() {
+		t.Fatal("new history should not be dirty")
+	}
+
+	h.Execute(&appendCmd{d: d, text: "a"})
+	if !h.IsDirty() {
+		t.Fatal("history should be dirty after Execute")
+	}
+	h.MarkSaved()
+	h.Execute(&appendCmd{d: d, text: "b"})
+	if !h.IsDirty() {
+		t.Fatal("history should be dirty after another Execute")
+	}
+	h.Undo()
+	if h.IsDirty() {
+		t.Fatal("undoing back to the savepoint should be clean")
+	}
+	h.Undo()
+	if !h.IsDirty() {
+		t.Fatal("undoing past the savepoint should be dirty")
+	}
+	h.Redo()
+	if h.IsDirty() {
+		t.Fatal("redoing back to the savepoint should be clean")
+	}
+
+	h.Undo()
+	h.Execute(&appendCmd{d: d, text: "z"})
+	h.Undo()
+	if !h.IsDirty() {
+		t.Fatal("savepoint discarded from redo history should never be clean again")
+	}
+}
+
+func TestSavepointWithMaxDepth(t *testing.T) {
+	d := &doc{}
+	h := NewHistory(1)
+	h.MarkSaved()
+	h.Execute(&appendCmd{d: d, text: "a"})
+	h.Execute(&appendCmd{d: d, text: "b"})
+	h.Undo()
+	if !h.IsDirty() {
+		t.Fatal("state after a is not the saved empty state")
+	}
+
+	h.Clear()
+	if h.IsDirty() || h.CanUndo() {
+		t.Fatal("Clear should reset history and mark it saved")
+	}
+}
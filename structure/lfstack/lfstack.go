@@ -0,0 +1,104 @@
+// Package lfstack 提供了可并发访问的无锁栈实现（Treiber 栈），方法与 stack.Stack 保持一致。
+package lfstack
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// node 是链式栈中的一个节点，发布到栈上之后不再修改。
+type node[T any] struct {
+	value T
+	next  *node[T]
+}
+
+// Stack 是一个可被任意数量的 goroutine 并发读写的无锁栈。
+// 每次 Push 都分配新节点，弹出的节点由垃圾回收器回收且不会被复用，
+// 因此比较并交换时指针相同即意味着栈顶未变，不存在 ABA 问题。
+// 零值 Stack 可以直接使用。
+type Stack[T any] struct {
+	top  atomic.Pointer[node[T]]
+	size atomic.Int64
+}
+
+// NewStack 创建并返回一个新的空栈。
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{}
+}
+
+// Len 返回栈中元素数量。
+// 在并发读写期间返回的只是一个近似值。
+func (s *Stack[T]) Len() int {
+	return int(max(s.size.Load(), 0))
+}
+
+// IsEmpty 判断栈是否为空。
+func (s *Stack[T]) IsEmpty() bool {
+	return s.top.Load() == nil
+}
+
+// Top 返回栈顶元素但不移除。
+func (s *Stack[T]) Top() (T, bool) {
+	top := s.top.Load()
+	if top == nil {
+		var zero T
+		return zero, false
+	}
+	return top.value, true
+}
+
+// Push 压入一个元素到栈顶。
+func (s *Stack[T]) Push(elem T) {
+	n := &node[T]{value: elem}
+	for {
+		n.next = s.top.Load()
+		if s.top.CompareAndSwap(n.next, n) {
+			s.size.Add(1)
+			return
+		}
+	}
+}
+
+// Pop 弹出并返回栈顶元素，若栈为空返回零值和 false。
+func (s *Stack[T]) Pop() (T, bool) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			var zero T
+			return zero, false
+		}
+		if s.top.CompareAndSwap(top, top.next) {
+			s.size.Add(-1)
+			return top.value, true
+		}
+	}
+}
+
+// Clear 清空栈中的所有元素。
+func (s *Stack[T]) Clear() {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			return
+		}
+		if s.top.CompareAndSwap(top, nil) {
+			// 只扣减本次实际摘下的节点数，与并发的 Push 和 Pop 保持一致
+			n := 0
+			for ; top != nil; top = top.next {
+				n++
+			}
+			s.size.Add(int64(-n))
+			return
+		}
+	}
+}
+
+// ToSlice 以自底向顶顺序返回调用时刻栈中的所有元素。
+func (s *Stack[T]) ToSlice() []T {
+	result := []T{}
+	for n := s.top.Load(); n != nil; n = n.next {
+		result = append(result, n.value)
+	}
+	slices.Reverse(result)
+	return result
+}
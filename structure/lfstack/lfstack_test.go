@@ -0,0 +1,169 @@
+package lfstack
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Repeater11/go-template/structure/stack"
+)
+
+// lifo 是 lfstack.Stack 与 stack.Stack 共有的方法集合。
+type lifo[T any] interface {
+	Push(elem T)
+	Pop() (T, bool)
+	Top() (T, bool)
+	Len() int
+	IsEmpty() bool
+	Clear()
+	ToSlice() []T
+}
+
+var (
+	_ lifo[int] = (*Stack[int])(nil)
+	_ lifo[int] = (*stack.Stack[int])(nil)
+)
+
+func TestPushPop(t *testing.T) {
+	s := NewStack[int]()
+	if !s.IsEmpty() || s.Len() != 0 {
+		t.Fatal("new stack should be empty")
+	}
+	for i := 0; i < 5; i++ {
+		s.Push(i)
+	}
+	if s.Len() != 5 {
+		t.Fatalf("expected len 5, got %d", s.Len())
+	}
+	if top, ok := s.Top(); !ok || top != 4 {
+		t.Fatalf("Top expected (4,true), got (%d,%v)", top, ok)
+	}
+	if got := s.ToSlice(); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("ToSlice expected bottom-to-top order, got %v", got)
+	}
+	for i := 4; i >= 0; i-- {
+		if v, ok := s.Pop(); !ok || v != i {
+			t.Fatalf("Pop expected (%d,true), got (%d,%v)", i, v, ok)
+		}
+	}
+	if _, ok := s.Pop(); ok {
+		t.Fatal("Pop on empty stack should fail")
+	}
+	if _, ok := s.Top(); ok {
+		t.Fatal("Top on empty stack should fail")
+	}
+}
+
+func TestZeroValueAndClear(t *testing.T) {
+	var s Stack[string]
+	s.Push("a")
+	s.Push("b")
+	s.Clear()
+	if !s.IsEmpty() || s.Len() != 0 {
+		t.Fatal("stack should be empty after Clear")
+	}
+	s.Push("c")
+	if top, _ := s.Top(); top != "c" || s.Len() != 1 {
+		t.Fatal("stack should be reusable after Clear")
+	}
+}
+
+// TestConcurrentFreeList 模拟多个 goroutine 共享缓冲区空闲链表的场景，
+// 验证每个元素不会丢失或被重复弹出。
+func TestConcurrentFreeList(t *testing.T) {
+	const (
+		workers = 8
+		buffers = 64
+		rounds  = 2000
+	)
+	s := NewStack[*[]byte]()
+	owned := make(map[*[]byte]bool)
+	for i := 0; i < buffers; i++ {
+		buf := make([]byte, 16)
+		owned[&buf] = true
+		s.Push(&buf)
+	}
+
+	var mu sync.Mutex
+	inUse := make(map[*[]byte]bool)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				buf, ok := s.Pop()
+				if !ok {
+					continue
+				}
+				mu.Lock()
+				if inUse[buf] {
+					mu.Unlock()
+					t.Error("buffer popped twice")
+					return
+				}
+				inUse[buf] = true
+				mu.Unlock()
+
+				(*buf)[0]++
+
+				mu.Lock()
+				delete(inUse, buf)
+				mu.Unlock()
+				s.Push(buf)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Len() != buffers {
+		t.Fatalf("expected %d buffers back on the free list, got %d", buffers, s.Len())
+	}
+	for _, buf := range s.ToSlice() {
+		if !owned[buf] {
+			t.Fatal("unknown buffer on the free list")
+		}
+		delete(owned, buf)
+	}
+	if len(owned) != 0 {
+		t.Fatalf("%d buffers lost", len(owned))
+	}
+}
+
+// lockedStack 是用互斥锁保护的 stack.Stack，作为基准测试的对照组。
+type lockedStack[T any] struct {
+	mu sync.Mutex
+	s  stack.Stack[T]
+}
+
+func (l *lockedStack[T]) Push(elem T) {
+	l.mu.Lock()
+	l.s.Push(elem)
+	l.mu.Unlock()
+}
+
+func (l *lockedStack[T]) Pop() (T, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.s.Pop()
+}
+
+func BenchmarkLockFree(b *testing.B) {
+	s := NewStack[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Push(1)
+			s.Pop()
+		}
+	})
+}
+
+func BenchmarkMutexStack(b *testing.B) {
+	s := &lockedStack[int]{}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Push(1)
+			s.Pop()
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// Package mpmc 提供了多生产者多消费者的无锁无界队列实现（Michael-Scott 队列）。
+package mpmc
+
+import "sync/atomic"
+
+// cacheLineSize 是用于填充的缓存行大小，避免 head 与 tail 伪共享。
+const cacheLineSize = 64
+
+// node 是链表中的一个节点。
+type node[T any] struct {
+	value T
+	next  atomic.Pointer[node[T]]
+}
+
+// Queue 是一个可被任意数量的 goroutine 并发读写的无锁无界队列。
+// 它提供与 queue.Queue 相同的 Push 和 Pop 方法，可以直接替换加锁的 queue.Queue。
+// 节点由垃圾回收器管理，因此不存在 ABA 问题。
+// Queue 的零值是一个可以直接使用的空队列，哨兵节点在首次使用时安装。
+type Queue[T any] struct {
+	head atomic.Pointer[node[T]] // 哨兵节点，其 next 为队首元素
+	_    [cacheLineSize - 8]byte
+	tail atomic.Pointer[node[T]] // 最后一个节点或其前驱
+	_    [cacheLineSize - 8]byte
+	size atomic.Int64
+}
+
+// NewQueue 创建并返回一个新的空队列。
+func NewQueue[T any]() *Queue[T] {
+	q := &Queue[T]{}
+	sentinel := &node[T]{}
+	q.head.Store(sentinel)
+	q.tail.Store(sentinel)
+	return q
+}
+
+// Len 返回队列中元素的数量。
+// 在并发读写期间返回的只是一个近似值。
+func (q *Queue[T]) Len() int {
+	return int(max(q.size.Load(), 0))
+}
+
+// IsEmpty 检查队列是否为空。
+func (q *Queue[T]) IsEmpty() bool {
+	q.ensureInit()
+	return q.head.Load().next.Load() == nil
+}
+
+// Push 在队列尾部添加一个元素。
+func (q *Queue[T]) Push(elem T) {
+	q.ensureInit()
+	n := &node[T]{value: elem}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// tail 落后了，帮助其他生产者推进
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, n) {
+			q.tail.CompareAndSwap(tail, n)
+			q.size.Add(1)
+			return
+		}
+	}
+}
+
+// Pop 移除并返回队列头部的元素。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Pop() (T, bool) {
+	q.ensureInit()
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if next == nil {
+			var zero T
+			return zero, false
+		}
+		if head == tail {
+			// tail 落后了，帮助生产者推进
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		// 必须在 CAS 之前读取值，CAS 成功后 next 成为新的哨兵节点
+		elem := next.value
+		if q.head.CompareAndSwap(head, next) {
+			q.size.Add(-1)
+			return elem, true
+		}
+	}
+}
+
+// Front 返回队列头部的元素但不移除它。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Front() (T, bool) {
+	q.ensureInit()
+	next := q.head.Load().next.Load()
+	if next == nil {
+		var zero T
+		return zero, false
+	}
+	return next.value, true
+}
+
+// ensureInit 为零值队列安装哨兵节点，可以被多个 goroutine 并发调用。
+// tail 总是最后安装，因此 tail 非 nil 时 head 一定也已安装。
+func (q *Queue[T]) ensureInit() {
+	if q.tail.Load() != nil {
+		return
+	}
+	q.head.CompareAndSwap(nil, &node[T]{})
+	// head 只有在 tail 安装之后才会前进，此时读到的仍是初始哨兵
+	q.tail.CompareAndSwap(nil, q.head.Load())
+}
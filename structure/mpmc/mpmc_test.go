@@ -0,0 +1,207 @@
+package mpmc
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Repeater11/go-template/structure/queue"
+)
+
+// fifo 是 mpmc.Queue 与 queue.Queue 共有的方法集合。
+type fifo[T any] interface {
+	Push(elem T)
+	Pop() (T, bool)
+	Len() int
+}
+
+var (
+	_ fifo[int] = (*Queue[int])(nil)
+	_ fifo[int] = (*queue.Queue[int])(nil)
+)
+
+func TestPushPopOrder(t *testing.T) {
+	q := NewQueue[int]()
+	if !q.IsEmpty() || q.Len() != 0 {
+		t.Fatal("new queue should be empty")
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Pop on empty queue should fail")
+	}
+	if _, ok := q.Front(); ok {
+		t.Fatal("Front on empty queue should fail")
+	}
+
+	for i := 0; i < 5; i++ {
+		q.Push(i)
+	}
+	if q.Len() != 5 || q.IsEmpty() {
+		t.Fatalf("expected len 5, got %d", q.Len())
+	}
+	if v, _ := q.Front(); v != 0 {
+		t.Fatalf("Front expected 0, got %d", v)
+	}
+	for i := 0; i < 5; i++ {
+		if v, ok := q.Pop(); !ok || v != i {
+			t.Fatalf("Pop expected (%d,true), got (%d,%v)", i, v, ok)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Fatal("queue should be empty after pops")
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var q Queue[int]
+	if !q.IsEmpty() {
+		t.Fatal("zero value queue should be empty")
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Pop on zero value queue should fail")
+	}
+	q.Push(1)
+	if v, ok := q.Pop(); !ok || v != 1 {
+		t.Fatalf("expected 1, got %d", v)
+	}
+
+	// 多个 goroutine 同时首次使用零值队列
+	const producers, perProducer = 8, 1000
+	var zero Queue[int]
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				zero.Push(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if zero.Len() != producers*perProducer {
+		t.Fatalf("expected %d elements, got %d", producers*perProducer, zero.Len())
+	}
+	n := 0
+	for _, ok := zero.Pop(); ok; _, ok = zero.Pop() {
+		n++
+	}
+	if n != producers*perProducer {
+		t.Fatalf("expected to pop %d elements, got %d", producers*perProducer, n)
+	}
+}
+
+// TestConcurrentLinearizability 验证并发下每个元素恰好被取出一次，
+// 并且任意消费者观察到的同一生产者的元素顺序与写入顺序一致。
+func TestConcurrentLinearizability(t *testing.T) {
+	const (
+		producers   = 8
+		consumers   = 8
+		perProducer = 5000
+	)
+	type item struct{ producer, seq int }
+
+	q := NewQueue[item]()
+	var seen [producers][perProducer]atomic.Int32
+	var popped atomic.Int64
+	var wg sync.WaitGroup
+
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for s := 0; s < perProducer; s++ {
+				q.Push(item{p, s})
+			}
+		}(p)
+	}
+
+	errs := make(chan string, consumers)
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for popped.Load() < producers*perProducer {
+				it, ok := q.Pop()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				popped.Add(1)
+				if it.seq <= last[it.producer] {
+					errs <- "per-producer order violated"
+					return
+				}
+				last[it.producer] = it.seq
+				seen[it.producer][it.seq].Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	for p := 0; p < producers; p++ {
+		for s := 0; s < perProducer; s++ {
+			if n := seen[p][s].Load(); n != 1 {
+				t.Fatalf("item (%d,%d) popped %d times", p, s, n)
+			}
+		}
+	}
+	if !q.IsEmpty() || q.Len() != 0 {
+		t.Fatalf("queue should be empty, len %d", q.Len())
+	}
+}
+
+// lockedQueue 是用互斥锁保护的 queue.Queue，作为基准测试的对照组。
+type lockedQueue[T any] struct {
+	mu sync.Mutex
+	q  queue.Queue[T]
+}
+
+func (l *lockedQueue[T]) Push(elem T) {
+	l.mu.Lock()
+	l.q.Push(elem)
+	l.mu.Unlock()
+}
+
+func (l *lockedQueue[T]) Pop() (T, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.q.Pop()
+}
+
+func (l *lockedQueue[T]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.q.Len()
+}
+
+func benchmarkContention(b *testing.B, q fifo[int]) {
+	b.SetParallelism(8)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				q.Push(i)
+			} else {
+				q.Pop()
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMPMC(b *testing.B) {
+	benchmarkContention(b, NewQueue[int]())
+}
+
+func BenchmarkMutexQueue(b *testing.B) {
+	benchmarkContention(b, &lockedQueue[int]{})
+}
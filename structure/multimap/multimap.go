@@ -0,0 +1,186 @@
+// Package multimap 提供了允许重复键的泛型有序映射实现，接口风格贴近 C++ std::multimap。
+package multimap
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/Repeater11/go-template/structure/treemap"
+)
+
+// entry 保存一个键值对，保留插入时的原始键。
+type entry[K, V any] struct {
+	key   K
+	value V
+}
+
+// MultiMap 是一个按键有序、允许重复键的泛型映射。
+// 键比较结果相等的键值对按插入顺序保存，遍历和删除时保持该顺序。
+type MultiMap[K, V any] struct {
+	tree *treemap.TreeMap[K, []entry[K, V]]
+	cmp  func(a, b K) int
+	size int
+}
+
+// NewMultiMap 使用自定义比较函数创建一个空的 MultiMap。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+func NewMultiMap[K, V any](cmp func(a, b K) int) *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		tree: treemap.NewTreeMap[K, []entry[K, V]](cmp),
+		cmp:  cmp,
+	}
+}
+
+// NewOrderedMultiMap 创建一个按键升序排列的空 MultiMap。
+// 仅适用于实现了 cmp.Ordered 接口的键类型（如 int, float64, string 等）。
+func NewOrderedMultiMap[K cmp.Ordered, V any]() *MultiMap[K, V] {
+	return NewMultiMap[K, V](cmp.Compare[K])
+}
+
+// Len 返回 MultiMap 中键值对的数量（包括重复键）。
+func (m *MultiMap[K, V]) Len() int {
+	return m.size
+}
+
+// IsEmpty 检查 MultiMap 是否为空。
+func (m *MultiMap[K, V]) IsEmpty() bool {
+	return m.size == 0
+}
+
+// Clear 移除 MultiMap 中的所有键值对。
+func (m *MultiMap[K, V]) Clear() {
+	m.tree.Clear()
+	m.size = 0
+}
+
+// Put 插入一个键值对，已存在的相同键不会被覆盖。
+func (m *MultiMap[K, V]) Put(key K, value V) {
+	group, _ := m.tree.Get(key)
+	m.tree.Put(key, append(group, entry[K, V]{key: key, value: value}))
+	m.size++
+}
+
+// Get 按插入顺序返回与 key 相等的所有键对应的值。
+// 如果键不存在，返回空切片。
+func (m *MultiMap[K, V]) Get(key K) []V {
+	group, _ := m.tree.Get(key)
+	result := make([]V, len(group))
+	for i, e := range group {
+		result[i] = e.value
+	}
+	return result
+}
+
+// Contains 检查 MultiMap 是否包含与 key 相等的键。
+func (m *MultiMap[K, V]) Contains(key K) bool {
+	return m.tree.Contains(key)
+}
+
+// Count 返回与 key 相等的键的个数。
+func (m *MultiMap[K, V]) Count(key K) int {
+	group, _ := m.tree.Get(key)
+	return len(group)
+}
+
+// EqualRange 返回一个按插入顺序遍历所有与 key 相等的键值对的迭代器。
+func (m *MultiMap[K, V]) EqualRange(key K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		group, _ := m.tree.Get(key)
+		for _, e := range group {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Remove 移除最早插入的一个与 key 相等的键值对。
+// 如果键不存在返回 false。
+func (m *MultiMap[K, V]) Remove(key K) bool {
+	group, ok := m.tree.Get(key)
+	if !ok {
+		return false
+	}
+	// 树中的键始终是组内第一个键，删除后需要重新插入以更新键
+	m.tree.Delete(key)
+	if len(group) > 1 {
+		group[0] = entry[K, V]{}
+		m.tree.Put(group[1].key, group[1:])
+	}
+	m.size--
+	return true
+}
+
+// RemoveAll 移除所有与 key 相等的键值对，返回移除的数量。
+func (m *MultiMap[K, V]) RemoveAll(key K) int {
+	group, ok := m.tree.Get(key)
+	if !ok {
+		return 0
+	}
+	m.tree.Delete(key)
+	m.size -= len(group)
+	return len(group)
+}
+
+// All 返回一个按键升序遍历所有键值对的迭代器，相等键按插入顺序产出。
+func (m *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return flatten(m.tree.All(), false)
+}
+
+// Backward 返回一个按键降序遍历所有键值对的迭代器，相等键按插入的逆序产出。
+func (m *MultiMap[K, V]) Backward() iter.Seq2[K, V] {
+	return flatten(m.tree.Backward(), true)
+}
+
+// Range 返回一个按键升序遍历区间 [lo, hi) 内键值对的迭代器。
+func (m *MultiMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return flatten(m.tree.Range(lo, hi), false)
+}
+
+// Keys 返回一个按升序遍历所有键的迭代器，重复键会按出现次数重复产出。
+func (m *MultiMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values 返回一个按键升序遍历所有值的迭代器。
+func (m *MultiMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Clone 创建并返回 MultiMap 的一个副本，键和值按赋值语义复制。
+func (m *MultiMap[K, V]) Clone() *MultiMap[K, V] {
+	clone := NewMultiMap[K, V](m.cmp)
+	for key, group := range m.tree.All() {
+		clone.tree.Put(key, append([]entry[K, V]{}, group...))
+	}
+	clone.size = m.size
+	return clone
+}
+
+// flatten 将按键分组的迭代器展开为逐个键值对的迭代器。
+func flatten[K, V any](groups iter.Seq2[K, []entry[K, V]], reverse bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, group := range groups {
+			for i := range group {
+				if reverse {
+					i = len(group) - 1 - i
+				}
+				if !yield(group[i].key, group[i].value) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package multimap
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func collect[K, V any](seq func(func(K, V) bool)) ([]K, []V) {
+	var keys []K
+	var values []V
+	for k, v := range seq {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return keys, values
+}
+
+func TestNewMultiMap(t *testing.T) {
+	m := NewOrderedMultiMap[string, int]()
+	if m.Len() != 0 || !m.IsEmpty() {
+		t.Fatalf("expected empty multimap, got len %d", m.Len())
+	}
+	if got := m.Get("a"); len(got) != 0 {
+		t.Fatalf("Get of missing key expected empty slice, got %v", got)
+	}
+}
+
+func TestPutGetCount(t *testing.T) {
+	m := NewOrderedMultiMap[string, int]()
+	m.Put("invoice", 1)
+	m.Put("refund", 2)
+	m.Put("invoice", 3)
+	m.Put("invoice", 4)
+
+	if m.Len() != 4 {
+		t.Fatalf("expected len 4, got %d", m.Len())
+	}
+	if m.Count("invoice") != 3 || m.Count("refund") != 1 || m.Count("x") != 0 {
+		t.Fatal("Count returned unexpected result")
+	}
+	if got := m.Get("invoice"); !slices.Equal(got, []int{1, 3, 4}) {
+		t.Fatalf("Get expected values in insertion order, got %v", got)
+	}
+
+	keys, values := collect(m.All())
+	if !slices.Equal(keys, []string{"invoice", "invoice", "invoice", "refund"}) {
+		t.Fatalf("All unexpected keys %v", keys)
+	}
+	if !slices.Equal(values, []int{1, 3, 4, 2}) {
+		t.Fatalf("All unexpected values %v", values)
+	}
+	_, values = collect(m.Backward())
+	if !slices.Equal(values, []int{2, 4, 3, 1}) {
+		t.Fatalf("Backward unexpected values %v", values)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	m := NewOrderedMultiMap[int, string]()
+	m.Put(1, "a")
+	m.Put(1, "b")
+	m.Put(1, "c")
+	m.Put(2, "d")
+
+	if !m.Remove(1) || m.Len() != 3 {
+		t.Fatal("Remove should erase exactly one entry")
+	}
+	if got := m.Get(1); !slices.Equal(got, []string{"b", "c"}) {
+		t.Fatalf("Remove should erase the earliest entry, got %v", got)
+	}
+	if m.Remove(3) {
+		t.Fatal("Remove of missing key should fail")
+	}
+	if got := m.RemoveAll(1); got != 2 || m.Contains(1) || m.Len() != 1 {
+		t.Fatalf("RemoveAll expected 2, got %d", got)
+	}
+	m.Clear()
+	if !m.IsEmpty() {
+		t.Fatal("multimap should be empty after Clear")
+	}
+}
+
+func TestEqualRangePreservesOriginalKeys(t *testing.T) {
+	m := NewMultiMap[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	m.Put("USD", 1)
+	m.Put("usd", 2)
+	m.Put("Usd", 3)
+	m.Put("eur", 4)
+
+	keys, values := collect(m.EqualRange("usd"))
+	if !slices.Equal(keys, []string{"USD", "usd", "Usd"}) || !slices.Equal(values, []int{1, 2, 3}) {
+		t.Fatalf("EqualRange unexpected result %v %v", keys, values)
+	}
+
+	m.Remove("usd")
+	keys, _ = collect(m.EqualRange("USD"))
+	if !slices.Equal(keys, []string{"usd", "Usd"}) {
+		t.Fatalf("unexpected keys after Remove %v", keys)
+	}
+}
+
+func TestRangeKeysValues(t *testing.T) {
+	m := NewOrderedMultiMap[int, int]()
+	for i := 0; i < 5; i++ {
+		m.Put(i, i*10)
+		m.Put(i, i*10+1)
+	}
+
+	keys, values := collect(m.Range(1, 3))
+	if !slices.Equal(keys, []int{1, 1, 2, 2}) || !slices.Equal(values, []int{10, 11, 20, 21}) {
+		t.Fatalf("Range(1,3) unexpected result %v %v", keys, values)
+	}
+	if got := slices.Collect(m.Keys()); len(got) != 10 || got[0] != 0 || got[9] != 4 {
+		t.Fatalf("Keys unexpected result %v", got)
+	}
+	if got := slices.Collect(m.Values()); got[1] != 1 || got[2] != 10 {
+		t.Fatalf("Values unexpected result %v", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	m := NewOrderedMultiMap[int, int]()
+	m.Put(1, 1)
+	m.Put(1, 2)
+	c := m.Clone()
+	c.Put(1, 3)
+	c.Remove(1)
+
+	if got := m.Get(1); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("modifying clone should not affect original, got %v", got)
+	}
+	if got := c.Get(1); !slices.Equal(got, []int{2, 3}) {
+		t.Fatalf("unexpected clone contents %v", got)
+	}
+}
@@ -0,0 +1,184 @@
+// Package multiset 提供了允许重复元素的泛型有序集合实现，接口风格贴近 C++ std::multiset。
+package multiset
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/Repeater11/go-template/structure/treemap"
+)
+
+// MultiSet 是一个按比较函数有序、允许重复元素的泛型集合。
+// 比较结果相等的元素按插入顺序保存，遍历和删除时保持该顺序。
+type MultiSet[T any] struct {
+	tree *treemap.TreeMap[T, []T]
+	cmp  func(a, b T) int
+	size int
+}
+
+// NewMultiSet 使用自定义比较函数创建一个 MultiSet。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+// 可选地，可以传入初始元素来填充 MultiSet。
+func NewMultiSet[T any](cmp func(a, b T) int, elements ...T) *MultiSet[T] {
+	s := &MultiSet[T]{
+		tree: treemap.NewTreeMap[T, []T](cmp),
+		cmp:  cmp,
+	}
+	s.Add(elements...)
+	return s
+}
+
+// NewOrderedMultiSet 创建一个按升序排列的 MultiSet。
+// 仅适用于实现了 cmp.Ordered 接口的类型（如 int, float64, string 等）。
+func NewOrderedMultiSet[T cmp.Ordered](elements ...T) *MultiSet[T] {
+	return NewMultiSet(cmp.Compare[T], elements...)
+}
+
+// Len 返回 MultiSet 中元素的数量（包括重复元素）。
+func (s *MultiSet[T]) Len() int {
+	return s.size
+}
+
+// IsEmpty 检查 MultiSet 是否为空。
+func (s *MultiSet[T]) IsEmpty() bool {
+	return s.size == 0
+}
+
+// Clear 移除 MultiSet 中的所有元素。
+func (s *MultiSet[T]) Clear() {
+	s.tree.Clear()
+	s.size = 0
+}
+
+// Add 向 MultiSet 中添加一个或多个元素。
+func (s *MultiSet[T]) Add(elements ...T) {
+	for _, elem := range elements {
+		group, _ := s.tree.Get(elem)
+		s.tree.Put(elem, append(group, elem))
+		s.size++
+	}
+}
+
+// Remove 移除最早插入的一个与 elem 相等的元素。
+// 如果元素不存在返回 false。
+func (s *MultiSet[T]) Remove(elem T) bool {
+	group, ok := s.tree.Get(elem)
+	if !ok {
+		return false
+	}
+	// 树中的键始终是组内第一个元素，删除后需要重新插入以更新键
+	s.tree.Delete(elem)
+	if len(group) > 1 {
+		var zero T
+		group[0] = zero
+		s.tree.Put(group[1], group[1:])
+	}
+	s.size--
+	return true
+}
+
+// RemoveAll 移除所有与 elem 相等的元素，返回移除的数量。
+func (s *MultiSet[T]) RemoveAll(elem T) int {
+	group, ok := s.tree.Get(elem)
+	if !ok {
+		return 0
+	}
+	s.tree.Delete(elem)
+	s.size -= len(group)
+	return len(group)
+}
+
+// Contains 检查 MultiSet 是否包含与 elem 相等的元素。
+func (s *MultiSet[T]) Contains(elem T) bool {
+	return s.tree.Contains(elem)
+}
+
+// Count 返回与 elem 相等的元素个数。
+func (s *MultiSet[T]) Count(elem T) int {
+	group, _ := s.tree.Get(elem)
+	return len(group)
+}
+
+// EqualRange 返回一个按插入顺序遍历所有与 elem 相等元素的迭代器。
+func (s *MultiSet[T]) EqualRange(elem T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		group, _ := s.tree.Get(elem)
+		for _, e := range group {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// First 返回最小的元素，存在多个时返回最早插入的那个。
+// 如果 MultiSet 为空，返回零值和 false。
+func (s *MultiSet[T]) First() (T, bool) {
+	_, group, ok := s.tree.Min()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return group[0], true
+}
+
+// Last 返回最大的元素，存在多个时返回最晚插入的那个。
+// 如果 MultiSet 为空，返回零值和 false。
+func (s *MultiSet[T]) Last() (T, bool) {
+	_, group, ok := s.tree.Max()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return group[len(group)-1], true
+}
+
+// All 返回一个按升序遍历所有元素的迭代器，相等元素按插入顺序产出。
+func (s *MultiSet[T]) All() iter.Seq[T] {
+	return flatten(s.tree.All(), false)
+}
+
+// Backward 返回一个按降序遍历所有元素的迭代器，相等元素按插入的逆序产出。
+func (s *MultiSet[T]) Backward() iter.Seq[T] {
+	return flatten(s.tree.Backward(), true)
+}
+
+// Range 返回一个按升序遍历区间 [lo, hi) 内元素的迭代器。
+func (s *MultiSet[T]) Range(lo, hi T) iter.Seq[T] {
+	return flatten(s.tree.Range(lo, hi), false)
+}
+
+// ToSlice 以升序返回所有元素。
+func (s *MultiSet[T]) ToSlice() []T {
+	result := make([]T, 0, s.size)
+	for elem := range s.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// Clone 创建并返回 MultiSet 的一个副本。
+func (s *MultiSet[T]) Clone() *MultiSet[T] {
+	clone := NewMultiSet(s.cmp)
+	for key, group := range s.tree.All() {
+		clone.tree.Put(key, append([]T{}, group...))
+	}
+	clone.size = s.size
+	return clone
+}
+
+// flatten 将按键分组的迭代器展开为逐个元素的迭代器。
+func flatten[T any](groups iter.Seq2[T, []T], reverse bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, group := range groups {
+			for i := range group {
+				if reverse {
+					i = len(group) - 1 - i
+				}
+				if !yield(group[i]) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package multiset
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+type item struct {
+	key int
+	tag string
+}
+
+func byKey(a, b item) int {
+	return cmp.Compare(a.key, b.key)
+}
+
+func tags(seq func(func(item) bool)) []string {
+	var result []string
+	for it := range seq {
+		result = append(result, it.tag)
+	}
+	return result
+}
+
+func TestNewMultiSet(t *testing.T) {
+	s := NewOrderedMultiSet[int]()
+	if s.Len() != 0 || !s.IsEmpty() {
+		t.Fatalf("expected empty multiset, got len %d", s.Len())
+	}
+	if _, ok := s.First(); ok {
+		t.Fatal("First on empty multiset should fail")
+	}
+
+	s = NewOrderedMultiSet(3, 1, 3, 2, 1)
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 1, 2, 3, 3}) {
+		t.Fatalf("expected [1 1 2 3 3], got %v", got)
+	}
+}
+
+func TestCountAndRemove(t *testing.T) {
+	s := NewOrderedMultiSet(5, 5, 5, 7)
+	if s.Count(5) != 3 || s.Count(6) != 0 {
+		t.Fatalf("unexpected counts: %d, %d", s.Count(5), s.Count(6))
+	}
+	if !s.Remove(5) || s.Count(5) != 2 || s.Len() != 3 {
+		t.Fatal("Remove should erase exactly one element")
+	}
+	if s.Remove(6) {
+		t.Fatal("Remove of missing element should fail")
+	}
+	if got := s.RemoveAll(5); got != 2 || s.Contains(5) || s.Len() != 1 {
+		t.Fatalf("RemoveAll expected 2, got %d", got)
+	}
+	if got := s.RemoveAll(5); got != 0 {
+		t.Fatalf("RemoveAll of missing element expected 0, got %d", got)
+	}
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Fatal("multiset should be empty after Clear")
+	}
+}
+
+func TestStableOrderAmongEquals(t *testing.T) {
+	s := NewMultiSet(byKey,
+		item{2, "a"},
+		item{1, "b"},
+		item{2, "c"},
+		item{1, "d"},
+		item{2, "e"},
+	)
+
+	if got := tags(s.All()); !slices.Equal(got, []string{"b", "d", "a", "c", "e"}) {
+		t.Fatalf("All unexpected order %v", got)
+	}
+	if got := tags(s.Backward()); !slices.Equal(got, []string{"e", "c", "a", "d", "b"}) {
+		t.Fatalf("Backward unexpected order %v", got)
+	}
+	if got := tags(s.EqualRange(item{key: 2})); !slices.Equal(got, []string{"a", "c", "e"}) {
+		t.Fatalf("EqualRange unexpected order %v", got)
+	}
+
+	s.Remove(item{key: 2})
+	if got := tags(s.EqualRange(item{key: 2})); !slices.Equal(got, []string{"c", "e"}) {
+		t.Fatalf("Remove should erase the earliest inserted element, got %v", got)
+	}
+
+	first, _ := s.First()
+	last, _ := s.Last()
+	if first.tag != "b" || last.tag != "e" {
+		t.Fatalf("First/Last expected b/e, got %s/%s", first.tag, last.tag)
+	}
+}
+
+func TestRange(t *testing.T) {
+	s := NewOrderedMultiSet(1, 2, 2, 3, 4, 4, 5)
+	if got := slices.Collect(s.Range(2, 5)); !slices.Equal(got, []int{2, 2, 3, 4, 4}) {
+		t.Fatalf("Range(2,5) unexpected result %v", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := NewOrderedMultiSet(1, 1, 2)
+	c := s.Clone()
+	c.Add(1)
+	c.RemoveAll(2)
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 1, 2}) {
+		t.Fatalf("modifying clone should not affect original, got %v", got)
+	}
+	if got := c.ToSlice(); !slices.Equal(got, []int{1, 1, 1}) {
+		t.Fatalf("unexpected clone contents %v", got)
+	}
+}
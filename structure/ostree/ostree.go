@@ -0,0 +1,311 @@
+// Package ostree 提供了支持排名与按序选择的泛型顺序统计树实现。
+package ostree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// node 是 AVL 树中的一个节点，相等的元素合并到同一个节点中计数。
+type node[T any] struct {
+	value  T
+	count  int // 与 value 相等的元素个数
+	size   int // 子树中元素的总个数（包括重复元素）
+	height int
+	left   *node[T]
+	right  *node[T]
+}
+
+// OSTree 是一个允许重复元素的顺序统计树，内部使用按子树大小增强的 AVL 树实现。
+// Insert、Delete、Rank、Select 和 CountRange 的时间复杂度均为 O(log n)。
+// 比较结果相等的元素只保留最先插入的那个作为代表，并记录出现次数。
+type OSTree[T any] struct {
+	root *node[T]
+	cmp  func(a, b T) int
+}
+
+// NewOSTree 使用自定义比较函数创建一个空的 OSTree。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+func NewOSTree[T any](cmp func(a, b T) int) *OSTree[T] {
+	return &OSTree[T]{cmp: cmp}
+}
+
+// NewOrderedOSTree 创建一个按升序排列的空 OSTree。
+// 仅适用于实现了 cmp.Ordered 接口的类型（如 int, float64, string 等）。
+func NewOrderedOSTree[T cmp.Ordered]() *OSTree[T] {
+	return NewOSTree(cmp.Compare[T])
+}
+
+// Len 返回 OSTree 中元素的数量（包括重复元素）。
+func (t *OSTree[T]) Len() int {
+	return size(t.root)
+}
+
+// IsEmpty 检查 OSTree 是否为空。
+func (t *OSTree[T]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// Clear 移除 OSTree 中的所有元素。
+func (t *OSTree[T]) Clear() {
+	t.root = nil
+}
+
+// Insert 插入一个元素，允许重复。
+func (t *OSTree[T]) Insert(value T) {
+	t.root = t.insert(t.root, value)
+}
+
+// insert 在以 n 为根的子树中插入元素，返回新的子树根。
+func (t *OSTree[T]) insert(n *node[T], value T) *node[T] {
+	if n == nil {
+		return &node[T]{value: value, count: 1, size: 1, height: 1}
+	}
+	switch c := t.cmp(value, n.value); {
+	case c < 0:
+		n.left = t.insert(n.left, value)
+	case c > 0:
+		n.right = t.insert(n.right, value)
+	default:
+		n.count++
+		n.size++
+		return n
+	}
+	return rebalance(n)
+}
+
+// Delete 移除一个与 value 相等的元素。
+// 如果元素不存在返回 false。
+func (t *OSTree[T]) Delete(value T) bool {
+	var removed bool
+	t.root = t.delete(t.root, value, false, &removed)
+	return removed
+}
+
+// DeleteAll 移除所有与 value 相等的元素，返回移除的数量。
+func (t *OSTree[T]) DeleteAll(value T) int {
+	count := t.Count(value)
+	if count > 0 {
+		var removed bool
+		t.root = t.delete(t.root, value, true, &removed)
+	}
+	return count
+}
+
+// delete 从以 n 为根的子树中移除 value，all 为 true 时移除所有重复元素。
+func (t *OSTree[T]) delete(n *node[T], value T, all bool, removed *bool) *node[T] {
+	if n == nil {
+		return nil
+	}
+	switch c := t.cmp(value, n.value); {
+	case c < 0:
+		n.left = t.delete(n.left, value, all, removed)
+	case c > 0:
+		n.right = t.delete(n.right, value, all, removed)
+	default:
+		*removed = true
+		if n.count > 1 && !all {
+			n.count--
+			n.size--
+			return n
+		}
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		// 用右子树的最小节点替换当前节点
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.right = removeMin(n.right)
+		successor.left = n.left
+		successor.right = n.right
+		n = successor
+	}
+	return rebalance(n)
+}
+
+// Contains 检查 OSTree 是否包含与 value 相等的元素。
+func (t *OSTree[T]) Contains(value T) bool {
+	return t.Count(value) > 0
+}
+
+// Count 返回与 value 相等的元素个数。
+func (t *OSTree[T]) Count(value T) int {
+	n := t.root
+	for n != nil {
+		switch c := t.cmp(value, n.value); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.count
+		}
+	}
+	return 0
+}
+
+// Rank 返回严格小于 value 的元素个数。
+func (t *OSTree[T]) Rank(value T) int {
+	rank := 0
+	n := t.root
+	for n != nil {
+		switch c := t.cmp(value, n.value); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			rank += size(n.left) + n.count
+			n = n.right
+		default:
+			return rank + size(n.left)
+		}
+	}
+	return rank
+}
+
+// Select 返回第 k 小的元素（k 从 0 开始）。
+// 如果 k 越界，返回零值和 false。
+func (t *OSTree[T]) Select(k int) (T, bool) {
+	if k < 0 || k >= t.Len() {
+		var zero T
+		return zero, false
+	}
+	n := t.root
+	for {
+		leftSize := size(n.left)
+		switch {
+		case k < leftSize:
+			n = n.left
+		case k < leftSize+n.count:
+			return n.value, true
+		default:
+			k -= leftSize + n.count
+			n = n.right
+		}
+	}
+}
+
+// CountRange 返回位于区间 [lo, hi) 内的元素个数。
+func (t *OSTree[T]) CountRange(lo, hi T) int {
+	if t.cmp(lo, hi) >= 0 {
+		return 0
+	}
+	return t.Rank(hi) - t.Rank(lo)
+}
+
+// Min 返回最小的元素。
+// 如果 OSTree 为空，返回零值和 false。
+func (t *OSTree[T]) Min() (T, bool) {
+	return t.Select(0)
+}
+
+// Max 返回最大的元素。
+// 如果 OSTree 为空，返回零值和 false。
+func (t *OSTree[T]) Max() (T, bool) {
+	return t.Select(t.Len() - 1)
+}
+
+// All 返回一个按升序遍历所有元素的迭代器，重复元素会按出现次数重复产出。
+func (t *OSTree[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ascend(t.root, yield)
+	}
+}
+
+// ToSlice 以升序返回所有元素。
+func (t *OSTree[T]) ToSlice() []T {
+	result := make([]T, 0, t.Len())
+	for v := range t.All() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// ascend 中序遍历子树，yield 返回 false 时停止并返回 false。
+func ascend[T any](n *node[T], yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	if !ascend(n.left, yield) {
+		return false
+	}
+	for i := 0; i < n.count; i++ {
+		if !yield(n.value) {
+			return false
+		}
+	}
+	return ascend(n.right, yield)
+}
+
+// size 返回子树中元素的总个数，nil 视为 0。
+func size[T any](n *node[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// height 返回子树高度，nil 视为 0。
+func height[T any](n *node[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update 根据子节点重新计算 n 的高度和大小。
+func update[T any](n *node[T]) {
+	n.height = max(height(n.left), height(n.right)) + 1
+	n.size = size(n.left) + size(n.right) + n.count
+}
+
+// rotateLeft 左旋并返回新的子树根。
+func rotateLeft[T any](n *node[T]) *node[T] {
+	x := n.right
+	n.right = x.left
+	x.left = n
+	update(n)
+	update(x)
+	return x
+}
+
+// rotateRight 右旋并返回新的子树根。
+func rotateRight[T any](n *node[T]) *node[T] {
+	x := n.left
+	n.left = x.right
+	x.right = n
+	update(n)
+	update(x)
+	return x
+}
+
+// rebalance 更新 n 的统计信息并在失衡时旋转，返回新的子树根。
+func rebalance[T any](n *node[T]) *node[T] {
+	update(n)
+	switch bf := height(n.left) - height(n.right); {
+	case bf > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	case bf < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
+
+// removeMin 从子树中摘除最小节点（不释放），返回新的子树根。
+func removeMin[T any](n *node[T]) *node[T] {
+	if n.left == nil {
+		return n.right
+	}
+	n.left = removeMin(n.left)
+	return rebalance(n)
+}
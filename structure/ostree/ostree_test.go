@@ -0,0 +1,170 @@
+package ostree
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// checkInvariants 校验 AVL 平衡性与子树大小，返回子树高度。
+func checkInvariants[T any](t *testing.T, n *node[T]) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	lh := checkInvariants(t, n.left)
+	rh := checkInvariants(t, n.right)
+	if lh-rh > 1 || rh-lh > 1 {
+		t.Fatalf("unbalanced node: heights %d and %d", lh, rh)
+	}
+	if n.size != size(n.left)+size(n.right)+n.count {
+		t.Fatal("subtree size is out of date")
+	}
+	return max(lh, rh) + 1
+}
+
+func TestNewOSTree(t *testing.T) {
+	tr := NewOrderedOSTree[int]()
+	if tr.Len() != 0 || !tr.IsEmpty() {
+		t.Fatalf("expected empty tree, got len %d", tr.Len())
+	}
+	if _, ok := tr.Select(0); ok {
+		t.Fatal("Select on empty tree should fail")
+	}
+	if _, ok := tr.Min(); ok {
+		t.Fatal("Min on empty tree should fail")
+	}
+	if tr.Delete(1) {
+		t.Fatal("Delete on empty tree should fail")
+	}
+}
+
+func TestRankSelectWithDuplicates(t *testing.T) {
+	tr := NewOrderedOSTree[int]()
+	for _, v := range []int{5, 1, 3, 3, 3, 9, 7} {
+		tr.Insert(v)
+	}
+	// 排序后：1 3 3 3 5 7 9
+	if tr.Len() != 7 {
+		t.Fatalf("expected len 7, got %d", tr.Len())
+	}
+	if got := tr.ToSlice(); !slices.Equal(got, []int{1, 3, 3, 3, 5, 7, 9}) {
+		t.Fatalf("unexpected contents %v", got)
+	}
+
+	ranks := map[int]int{0: 0, 1: 0, 2: 1, 3: 1, 4: 4, 5: 4, 6: 5, 10: 7}
+	for v, want := range ranks {
+		if got := tr.Rank(v); got != want {
+			t.Errorf("Rank(%d) expected %d, got %d", v, want, got)
+		}
+	}
+	for k, want := range []int{1, 3, 3, 3, 5, 7, 9} {
+		if got, ok := tr.Select(k); !ok || got != want {
+			t.Errorf("Select(%d) expected %d, got %d", k, want, got)
+		}
+	}
+	if _, ok := tr.Select(7); ok {
+		t.Fatal("Select out of range should fail")
+	}
+	if got := tr.Count(3); got != 3 {
+		t.Fatalf("Count(3) expected 3, got %d", got)
+	}
+	if got := tr.CountRange(3, 7); got != 4 {
+		t.Fatalf("CountRange(3,7) expected 4, got %d", got)
+	}
+	if got := tr.CountRange(7, 3); got != 0 {
+		t.Fatalf("CountRange with lo > hi expected 0, got %d", got)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tr := NewOrderedOSTree[int]()
+	for _, v := range []int{2, 2, 2, 4, 6} {
+		tr.Insert(v)
+	}
+	if !tr.Delete(2) || tr.Count(2) != 2 || tr.Len() != 4 {
+		t.Fatal("Delete should remove a single occurrence")
+	}
+	if got := tr.DeleteAll(2); got != 2 || tr.Contains(2) {
+		t.Fatalf("DeleteAll expected 2 removals, got %d", got)
+	}
+	if got := tr.DeleteAll(2); got != 0 {
+		t.Fatalf("DeleteAll of missing value expected 0, got %d", got)
+	}
+	if got := tr.ToSlice(); !slices.Equal(got, []int{4, 6}) {
+		t.Fatalf("unexpected contents %v", got)
+	}
+}
+
+func TestRandomAgainstSortedSlice(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	tr := NewOrderedOSTree[int]()
+	var ref []int
+
+	for i := 0; i < 5000; i++ {
+		v := rng.Intn(500)
+		if rng.Intn(3) == 0 {
+			idx := sort.SearchInts(ref, v)
+			want := idx < len(ref) && ref[idx] == v
+			if want {
+				ref = slices.Delete(ref, idx, idx+1)
+			}
+			if got := tr.Delete(v); got != want {
+				t.Fatalf("Delete(%d) expected %v, got %v", v, want, got)
+			}
+		} else {
+			idx := sort.SearchInts(ref, v)
+			ref = slices.Insert(ref, idx, v)
+			tr.Insert(v)
+		}
+
+		q := rng.Intn(500)
+		if got, want := tr.Rank(q), sort.SearchInts(ref, q); got != want {
+			t.Fatalf("Rank(%d) expected %d, got %d", q, want, got)
+		}
+		if len(ref) > 0 {
+			k := rng.Intn(len(ref))
+			if got, _ := tr.Select(k); got != ref[k] {
+				t.Fatalf("Select(%d) expected %d, got %d", k, ref[k], got)
+			}
+		}
+	}
+	checkInvariants(t, tr.root)
+	if !slices.Equal(tr.ToSlice(), ref) {
+		t.Fatal("tree contents diverged from reference slice")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tr := NewOrderedOSTree[float64]()
+	for i := 1; i <= 100; i++ {
+		tr.Insert(float64(i))
+	}
+	p90, _ := tr.Select(tr.Len() * 90 / 100)
+	if p90 != 91 {
+		t.Fatalf("90th percentile expected 91, got %v", p90)
+	}
+	if largest, _ := tr.Max(); largest != 100 {
+		t.Fatalf("Max expected 100, got %v", largest)
+	}
+}
+
+func BenchmarkInsert(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	tr := NewOrderedOSTree[int]()
+	for i := 0; i < b.N; i++ {
+		tr.Insert(rng.Int())
+	}
+}
+
+func BenchmarkRank(b *testing.B) {
+	tr := NewOrderedOSTree[int]()
+	for i := 0; i < 1<<16; i++ {
+		tr.Insert(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.Rank(i & (1<<16 - 1))
+	}
+}
@@ -0,0 +1,408 @@
+package persistent
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+const (
+	// hashBits 是哈希值的位数。
+	hashBits = 64
+	// mapBits 是 HAMT 每层使用的哈希位数。
+	mapBits = 5
+	// mapMask 用于取出一层的哈希位。
+	mapMask = 1<<mapBits - 1
+)
+
+// defaultSeed 是默认哈希函数使用的种子，在进程内保持不变。
+var defaultSeed = maphash.MakeSeed()
+
+// defaultHash 使用 maphash.Comparable 计算键的哈希值。
+func defaultHash[K comparable](key K) uint64 {
+	return maphash.Comparable(defaultSeed, key)
+}
+
+// hentry 是 HAMT 节点中的一项，node 不为 nil 时表示子树，否则表示一个键值对。
+type hentry[K comparable, V any] struct {
+	node  *hnode[K, V]
+	hash  uint64
+	key   K
+	value V
+}
+
+// hnode 是 HAMT 中的一个节点。
+// 普通节点用 bitmap 标记存在的分支，entries 按分支号顺序紧凑保存；
+// 哈希位耗尽后的冲突节点 bitmap 为 0，entries 中是哈希值完全相同的键值对。
+type hnode[K comparable, V any] struct {
+	edit    *editToken
+	bitmap  uint32
+	entries []hentry[K, V]
+}
+
+// editable 返回可以在 edit 下原地修改的节点，edit 为 nil 时总是返回副本。
+func (n *hnode[K, V]) editable(edit *editToken) *hnode[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &hnode[K, V]{edit: edit, bitmap: n.bitmap, entries: slices.Clone(n.entries)}
+}
+
+// Map 是一个不可变的哈希映射，使用哈希数组映射字典树（HAMT）实现。
+// Set 和 Delete 只复制从根到目标位置路径上的节点，时间复杂度为 O(log32 n)。
+// 哈希函数可以通过 NewMapWithHasher 替换，默认使用 maphash.Comparable。
+// nil *Map 表示使用默认哈希函数的空映射。
+type Map[K comparable, V any] struct {
+	root *hnode[K, V]
+	size int
+	hash func(K) uint64
+}
+
+// NewMap 创建一个使用默认哈希函数的空 Map。
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return NewMapWithHasher[K, V](defaultHash[K])
+}
+
+// NewMapWithHasher 创建一个使用指定哈希函数的空 Map。
+// 相等的键必须拥有相同的哈希值；哈希值冲突的键仍然可以正确保存，只是查找会变慢。
+func NewMapWithHasher[K comparable, V any](hash func(K) uint64) *Map[K, V] {
+	return &Map[K, V]{root: &hnode[K, V]{}, hash: hash}
+}
+
+// Len 返回 Map 中键值对的数量。
+func (m *Map[K, V]) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.size
+}
+
+// IsEmpty 检查 Map 是否为空。
+func (m *Map[K, V]) IsEmpty() bool {
+	return m.Len() == 0
+}
+
+// Get 返回键对应的值。
+// 如果键不存在，返回零值和 false。
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	if m.IsEmpty() {
+		var zero V
+		return zero, false
+	}
+	return m.root.get(0, m.hash(key), key)
+}
+
+// Contains 检查键是否存在。
+func (m *Map[K, V]) Contains(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Set 返回将键对应的值设置为 value 后的新 Map，原 Map 不变。
+func (m *Map[K, V]) Set(key K, value V) *Map[K, V] {
+	next := m.copy()
+	next.set(key, value, nil)
+	return next
+}
+
+// Delete 返回删除键之后的新 Map，原 Map 不变。
+// 如果键不存在，返回原 Map。
+func (m *Map[K, V]) Delete(key K) *Map[K, V] {
+	if !m.Contains(key) {
+		return m
+	}
+	next := m.copy()
+	next.delete(key, nil)
+	return next
+}
+
+// All 返回一个遍历所有键值对的迭代器，遍历顺序由哈希值决定。
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if !m.IsEmpty() {
+			m.root.all(yield)
+		}
+	}
+}
+
+// Keys 返回一个遍历所有键的迭代器。
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values 返回一个遍历所有值的迭代器。
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Transient 返回一个以当前内容为初始值的可变构建器，原 Map 不受后续修改影响。
+func (m *Map[K, V]) Transient() *MapTransient[K, V] {
+	return &MapTransient[K, V]{m: *m.copy(), edit: &editToken{}}
+}
+
+// MapEqual 判断两个 Map 是否拥有相同的键值对，不要求使用相同的哈希函数。
+func MapEqual[K, V comparable](a, b *Map[K, V]) bool {
+	return MapEqualFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// MapEqualFunc 使用 eq 比较值，判断两个 Map 是否拥有相同的键值对。
+func MapEqualFunc[K comparable, V any](a, b *Map[K, V], eq func(x, y V) bool) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	if a.IsEmpty() || a.root == b.root {
+		return true
+	}
+	for k, x := range a.All() {
+		y, ok := b.Get(k)
+		if !ok || !eq(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// MapTransient 是 Map 的可变构建器，用于批量修改时避免每次操作都复制路径。
+// 调用 Persistent 之后 MapTransient 不能再使用。MapTransient 不是并发安全的。
+type MapTransient[K comparable, V any] struct {
+	m    Map[K, V]
+	edit *editToken
+}
+
+// Len 返回构建器中键值对的数量。
+func (t *MapTransient[K, V]) Len() int {
+	return t.m.size
+}
+
+// Get 返回键对应的值。
+// 如果键不存在，返回零值和 false。
+func (t *MapTransient[K, V]) Get(key K) (V, bool) {
+	t.ensureEditable()
+	return t.m.Get(key)
+}
+
+// Set 设置键对应的值。
+func (t *MapTransient[K, V]) Set(key K, value V) {
+	t.ensureEditable()
+	t.m.set(key, value, t.edit)
+}
+
+// Delete 删除键，如果键不存在返回 false。
+func (t *MapTransient[K, V]) Delete(key K) bool {
+	t.ensureEditable()
+	return t.m.delete(key, t.edit)
+}
+
+// Persistent 返回包含构建结果的不可变 Map，之后 MapTransient 不能再使用。
+func (t *MapTransient[K, V]) Persistent() *Map[K, V] {
+	t.ensureEditable()
+	t.edit = nil
+	m := t.m
+	return &m
+}
+
+// ensureEditable 检查构建器是否仍然可用。
+func (t *MapTransient[K, V]) ensureEditable() {
+	if t.edit == nil {
+		panic("persistent: transient used after Persistent")
+	}
+}
+
+// copy 返回 Map 的浅拷贝，nil Map 会被初始化为使用默认哈希函数的空映射。
+func (m *Map[K, V]) copy() *Map[K, V] {
+	if m == nil {
+		return NewMap[K, V]()
+	}
+	next := *m
+	return &next
+}
+
+// set 设置键对应的值，edit 为 nil 时复制路径上的节点。
+func (m *Map[K, V]) set(key K, value V, edit *editToken) {
+	root, added := m.root.set(0, hentry[K, V]{hash: m.hash(key), key: key, value: value}, edit)
+	m.root = root
+	if added {
+		m.size++
+	}
+}
+
+// delete 删除键，edit 为 nil 时复制路径上的节点。
+func (m *Map[K, V]) delete(key K, edit *editToken) bool {
+	root, removed := m.root.delete(0, m.hash(key), key, edit)
+	if !removed {
+		return false
+	}
+	if root == nil {
+		root = &hnode[K, V]{edit: edit}
+	}
+	m.root = root
+	m.size--
+	return true
+}
+
+// index 返回哈希值在 shift 层对应的位以及它在 entries 中的位置。
+func (n *hnode[K, V]) index(shift uint, hash uint64) (bit uint32, idx int) {
+	bit = 1 << ((hash >> shift) & mapMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// get 在以 n 为根、位于 shift 层的子树中查找键。
+func (n *hnode[K, V]) get(shift uint, hash uint64, key K) (V, bool) {
+	for shift < hashBits {
+		bit, idx := n.index(shift, hash)
+		if n.bitmap&bit == 0 {
+			var zero V
+			return zero, false
+		}
+		e := n.entries[idx]
+		if e.node == nil {
+			if e.hash == hash && e.key == key {
+				return e.value, true
+			}
+			var zero V
+			return zero, false
+		}
+		n = e.node
+		shift += mapBits
+	}
+	for _, e := range n.entries {
+		if e.key == key {
+			return e.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// set 将键值对 leaf 放入以 n 为根、位于 shift 层的子树，返回新的子树以及是否新增了键。
+func (n *hnode[K, V]) set(shift uint, leaf hentry[K, V], edit *editToken) (*hnode[K, V], bool) {
+	if shift >= hashBits {
+		result := n.editable(edit)
+		for i, e := range result.entries {
+			if e.key == leaf.key {
+				result.entries[i] = leaf
+				return result, false
+			}
+		}
+		result.entries = append(result.entries, leaf)
+		return result, true
+	}
+
+	bit, idx := n.index(shift, leaf.hash)
+	result := n.editable(edit)
+	if n.bitmap&bit == 0 {
+		result.bitmap |= bit
+		result.entries = slices.Insert(result.entries, idx, leaf)
+		return result, true
+	}
+
+	e := n.entries[idx]
+	switch {
+	case e.node != nil:
+		child, added := e.node.set(shift+mapBits, leaf, edit)
+		result.entries[idx] = hentry[K, V]{node: child}
+		return result, added
+	case e.hash == leaf.hash && e.key == leaf.key:
+		result.entries[idx] = leaf
+		return result, false
+	default:
+		result.entries[idx] = hentry[K, V]{node: merge(shift+mapBits, e, leaf, edit)}
+		return result, true
+	}
+}
+
+// merge 创建一个包含两个不同键值对的子树。
+func merge[K comparable, V any](shift uint, a, b hentry[K, V], edit *editToken) *hnode[K, V] {
+	if shift >= hashBits {
+		return &hnode[K, V]{edit: edit, entries: []hentry[K, V]{a, b}}
+	}
+	bitA := uint32(1) << ((a.hash >> shift) & mapMask)
+	bitB := uint32(1) << ((b.hash >> shift) & mapMask)
+	if bitA == bitB {
+		child := merge(shift+mapBits, a, b, edit)
+		return &hnode[K, V]{edit: edit, bitmap: bitA, entries: []hentry[K, V]{{node: child}}}
+	}
+	if bitA > bitB {
+		a, b = b, a
+	}
+	return &hnode[K, V]{edit: edit, bitmap: bitA | bitB, entries: []hentry[K, V]{a, b}}
+}
+
+// delete 从以 n 为根、位于 shift 层的子树中删除键，返回新的子树以及是否删除了键。
+// 子树变空时返回 nil。
+func (n *hnode[K, V]) delete(shift uint, hash uint64, key K, edit *editToken) (*hnode[K, V], bool) {
+	if shift >= hashBits {
+		i := slices.IndexFunc(n.entries, func(e hentry[K, V]) bool { return e.key == key })
+		if i < 0 {
+			return n, false
+		}
+		if len(n.entries) == 1 {
+			return nil, true
+		}
+		result := n.editable(edit)
+		result.entries = slices.Delete(result.entries, i, i+1)
+		return result, true
+	}
+
+	bit, idx := n.index(shift, hash)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	e := n.entries[idx]
+	var replacement *hentry[K, V]
+	if e.node != nil {
+		child, removed := e.node.delete(shift+mapBits, hash, key, edit)
+		if !removed {
+			return n, false
+		}
+		if child != nil {
+			// 只剩一个键值对的子树被提升为叶子，保持结构紧凑
+			if len(child.entries) == 1 && child.entries[0].node == nil {
+				replacement = &child.entries[0]
+			} else {
+				replacement = &hentry[K, V]{node: child}
+			}
+		}
+	} else if e.hash != hash || e.key != key {
+		return n, false
+	}
+
+	if replacement == nil && len(n.entries) == 1 {
+		return nil, true
+	}
+	result := n.editable(edit)
+	if replacement != nil {
+		result.entries[idx] = *replacement
+	} else {
+		result.bitmap &^= bit
+		result.entries = slices.Delete(result.entries, idx, idx+1)
+	}
+	return result, true
+}
+
+// all 遍历子树中的所有键值对，yield 返回 false 时停止并返回 false。
+func (n *hnode[K, V]) all(yield func(K, V) bool) bool {
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.all(yield) {
+				return false
+			}
+		} else if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
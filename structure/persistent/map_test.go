@@ -0,0 +1,217 @@
+package persistent
+
+import (
+	"maps"
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func checkMap(t *testing.T, m *Map[int, int], model map[int]int) {
+	t.Helper()
+	if m.Len() != len(model) {
+		t.Fatalf("expected len %d, got %d", len(model), m.Len())
+	}
+	for k, v := range model {
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Fatalf("Get(%d) expected %d, got %d (%v)", k, v, got, ok)
+		}
+	}
+	if got := maps.Collect(m.All()); !maps.Equal(got, model) {
+		t.Fatalf("All mismatch: expected %d entries, got %d", len(model), len(got))
+	}
+}
+
+func TestMapSetGetDelete(t *testing.T) {
+	var m *Map[string, int]
+	if _, ok := m.Get("a"); ok {
+		t.Fatal("Get on nil map should fail")
+	}
+
+	m1 := m.Set("a", 1)
+	m2 := m1.Set("b", 2)
+	m3 := m2.Set("a", 10)
+	if v, _ := m3.Get("a"); v != 10 || m3.Len() != 2 {
+		t.Fatalf("expected a=10 len 2, got %d %d", v, m3.Len())
+	}
+	if v, _ := m2.Get("a"); v != 1 {
+		t.Fatal("old version should be unchanged")
+	}
+
+	m4 := m3.Delete("a")
+	if m4.Contains("a") || m4.Len() != 1 || !m3.Contains("a") {
+		t.Fatal("Delete should only affect the new version")
+	}
+	if m4.Delete("missing") != m4 {
+		t.Fatal("Delete of a missing key should return the same map")
+	}
+	if !m4.Delete("b").IsEmpty() {
+		t.Fatal("deleting the last key should give an empty map")
+	}
+}
+
+func TestMapPersistence(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	type version struct {
+		m     *Map[int, int]
+		model map[int]int
+	}
+	versions := []version{{NewMap[int, int](), map[int]int{}}}
+	for i := 0; i < 5000; i++ {
+		cur := versions[r.Intn(len(versions))]
+		model := maps.Clone(cur.model)
+		k := r.Intn(500)
+		var next *Map[int, int]
+		if r.Intn(3) == 0 {
+			next = cur.m.Delete(k)
+			delete(model, k)
+		} else {
+			next = cur.m.Set(k, i)
+			model[k] = i
+		}
+		versions = append(versions, version{next, model})
+	}
+	for _, v := range versions[len(versions)-200:] {
+		checkMap(t, v.m, v.model)
+	}
+}
+
+func TestMapCollisions(t *testing.T) {
+	// 只使用两个哈希值，迫使所有键进入冲突节点
+	m := NewMapWithHasher[int, int](func(k int) uint64 { return uint64(k % 2) })
+	model := map[int]int{}
+	for i := 0; i < 100; i++ {
+		m = m.Set(i, i*i)
+		model[i] = i * i
+	}
+	checkMap(t, m, model)
+	for i := 0; i < 100; i += 3 {
+		m = m.Delete(i)
+		delete(model, i)
+	}
+	checkMap(t, m, model)
+	if m.Delete(1000) != m {
+		t.Fatal("Delete of a missing colliding key should return the same map")
+	}
+}
+
+func TestMapPartialCollisions(t *testing.T) {
+	// 低位相同、高位不同的哈希值会形成深层的单链路径
+	m := NewMapWithHasher[int, int](func(k int) uint64 { return uint64(k) << 58 })
+	model := map[int]int{}
+	for i := 0; i < 64; i++ {
+		m = m.Set(i, i)
+		model[i] = i
+	}
+	checkMap(t, m, model)
+	for i := 0; i < 64; i++ {
+		m = m.Delete(i)
+		delete(model, i)
+		checkMap(t, m, model)
+	}
+}
+
+func TestMapTransient(t *testing.T) {
+	base := NewMap[int, int]().Set(1, 1)
+	tr := base.Transient()
+	for i := 0; i < 1000; i++ {
+		tr.Set(i, i*2)
+	}
+	for i := 0; i < 1000; i += 2 {
+		if !tr.Delete(i) {
+			t.Fatalf("Delete(%d) should succeed", i)
+		}
+	}
+	if tr.Delete(0) {
+		t.Fatal("Delete of a missing key should return false")
+	}
+	if v, ok := tr.Get(1); !ok || v != 2 || tr.Len() != 500 {
+		t.Fatalf("unexpected transient state %d len %d", v, tr.Len())
+	}
+	m := tr.Persistent()
+	if v, _ := base.Get(1); v != 1 || base.Len() != 1 {
+		t.Fatal("base map should be unchanged")
+	}
+
+	tr2 := m.Transient()
+	tr2.Set(1, -1)
+	if v, _ := m.Get(1); v != 2 {
+		t.Fatal("new transient modified an existing version")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("using a transient after Persistent should panic")
+		}
+	}()
+	tr.Set(5, 5)
+}
+
+func TestMapEqual(t *testing.T) {
+	a := NewMap[string, int]().Set("x", 1).Set("y", 2)
+	b := NewMapWithHasher[string, int](func(s string) uint64 { return uint64(len(s)) }).Set("y", 2).Set("x", 1)
+	if !MapEqual(a, b) {
+		t.Fatal("maps with the same entries should be equal regardless of hasher")
+	}
+	if MapEqual(a, b.Set("y", 3)) || MapEqual(a, a.Delete("x")) {
+		t.Fatal("different maps should not be equal")
+	}
+	if !MapEqual(nil, NewMap[string, int]()) {
+		t.Fatal("empty maps should be equal")
+	}
+	same := MapEqualFunc(a, b, func(x, y int) bool { return x%2 == y%2 })
+	if !same {
+		t.Fatal("MapEqualFunc should use the given comparison")
+	}
+}
+
+func TestMapIterators(t *testing.T) {
+	m := NewMap[string, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Set(strconv.Itoa(i), i)
+	}
+	sum := 0
+	for v := range m.Values() {
+		sum += v
+	}
+	if sum != 4950 {
+		t.Fatalf("expected sum 4950, got %d", sum)
+	}
+	count := 0
+	for range m.Keys() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Fatal("Keys should stop early")
+	}
+}
+
+func TestMapConcurrentReaders(t *testing.T) {
+	m := NewMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		m = m.Set(i, i)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := m
+			for i := 0; i < 1000; i++ {
+				if v, ok := local.Get(i); !ok || v != i {
+					t.Errorf("Get(%d) expected %d, got %d", i, i, v)
+					return
+				}
+				local = local.Set(i, -i)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := m.Get(5); v != 5 {
+		t.Fatal("shared map should be unchanged")
+	}
+}
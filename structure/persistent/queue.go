@@ -0,0 +1,195 @@
+package persistent
+
+import (
+	"iter"
+	"sync"
+)
+
+// cell 是惰性流中已求值的一个节点，nil 表示流的末尾。
+type cell[T any] struct {
+	head T
+	tail *stream[T]
+}
+
+// stream 是一个惰性求值并缓存结果的流，并发求值时只会计算一次。
+type stream[T any] struct {
+	once  sync.Once
+	thunk func() *cell[T]
+	cell  *cell[T]
+}
+
+// lazy 创建一个在首次访问时调用 thunk 求值的流。
+func lazy[T any](thunk func() *cell[T]) *stream[T] {
+	return &stream[T]{thunk: thunk}
+}
+
+// ready 创建一个已经求值为 c 的流。
+func ready[T any](c *cell[T]) *stream[T] {
+	s := &stream[T]{cell: c}
+	s.once.Do(func() {})
+	return s
+}
+
+// force 对流求值并返回其第一个节点，空流返回 nil。
+func (s *stream[T]) force() *cell[T] {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		s.cell = s.thunk()
+		s.thunk = nil
+	})
+	return s.cell
+}
+
+// concat 惰性地连接两个流，每次只求值一个节点。
+func concat[T any](a, b *stream[T]) *stream[T] {
+	return lazy(func() *cell[T] {
+		c := a.force()
+		if c == nil {
+			return b.force()
+		}
+		return &cell[T]{head: c.head, tail: concat(c.tail, b)}
+	})
+}
+
+// reversed 惰性地将栈转换为从栈底到栈顶的流，首次访问时一次性完成反转。
+func reversed[T any](s *Stack[T]) *stream[T] {
+	return lazy(func() *cell[T] {
+		var head *cell[T]
+		for elem := range s.All() {
+			head = &cell[T]{head: elem, tail: ready(head)}
+		}
+		return head
+	})
+}
+
+// Queue 是一个不可变的先进先出队列，使用 Okasaki 的银行家队列实现。
+// 队列由惰性的前端流和以栈保存的后端组成，后端长度超过前端时将反转后的后端惰性地接到前端之后。
+// 由于流的求值结果会被缓存，即使旧版本被反复使用，Push 和 Pop 的均摊时间复杂度仍为 O(1)。
+// nil *Queue 表示空队列。
+type Queue[T any] struct {
+	front *stream[T]
+	lenF  int
+	rear  *Stack[T] // 栈顶是队尾
+	lenR  int
+	back  T
+}
+
+// NewQueue 创建一个依次加入 elements 的队列。
+func NewQueue[T any](elements ...T) *Queue[T] {
+	var q *Queue[T]
+	for _, elem := range elements {
+		q = q.Push(elem)
+	}
+	return q
+}
+
+// Len 返回队列中元素的数量。
+func (q *Queue[T]) Len() int {
+	if q == nil {
+		return 0
+	}
+	return q.lenF + q.lenR
+}
+
+// IsEmpty 检查队列是否为空。
+func (q *Queue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// Front 返回队列头部的元素。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Front() (T, bool) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return q.front.force().head, true
+}
+
+// Back 返回队列尾部的元素。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) Back() (T, bool) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return q.back, true
+}
+
+// Push 返回在队列尾部加入 elem 后的新队列，原队列不变。
+func (q *Queue[T]) Push(elem T) *Queue[T] {
+	if q == nil {
+		q = &Queue[T]{}
+	}
+	return balance(q.front, q.lenF, q.rear.Push(elem), q.lenR+1, elem)
+}
+
+// Pop 返回队列头部的元素以及移除它之后的新队列，原队列不变。
+// 如果队列为空，返回零值、空队列和 false。
+func (q *Queue[T]) Pop() (T, *Queue[T], bool) {
+	if q.IsEmpty() {
+		var zero T
+		return zero, nil, false
+	}
+	c := q.front.force()
+	if q.Len() == 1 {
+		return c.head, nil, true
+	}
+	return c.head, balance(c.tail, q.lenF-1, q.rear, q.lenR, q.back), true
+}
+
+// All 返回一个从队头到队尾遍历元素的迭代器。
+func (q *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if q.IsEmpty() {
+			return
+		}
+		for c := q.front.force(); c != nil; c = c.tail.force() {
+			if !yield(c.head) {
+				return
+			}
+		}
+		for c := reversed(q.rear).force(); c != nil; c = c.tail.force() {
+			if !yield(c.head) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 以从队头到队尾的顺序返回所有元素。
+func (q *Queue[T]) ToSlice() []T {
+	result := make([]T, 0, q.Len())
+	for elem := range q.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// QueueEqual 判断两个队列是否按相同顺序拥有相同的元素。
+func QueueEqual[T comparable](a, b *Queue[T]) bool {
+	if a == b {
+		return true
+	}
+	if a.Len() != b.Len() {
+		return false
+	}
+	nextB, stop := iter.Pull(b.All())
+	defer stop()
+	for x := range a.All() {
+		if y, _ := nextB(); x != y {
+			return false
+		}
+	}
+	return true
+}
+
+// balance 在后端长度超过前端时将反转后的后端接到前端之后，保持 lenR <= lenF。
+func balance[T any](front *stream[T], lenF int, rear *Stack[T], lenR int, back T) *Queue[T] {
+	if lenR <= lenF {
+		return &Queue[T]{front: front, lenF: lenF, rear: rear, lenR: lenR, back: back}
+	}
+	return &Queue[T]{front: concat(front, reversed(rear)), lenF: lenF + lenR, back: back}
+}
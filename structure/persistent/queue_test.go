@@ -0,0 +1,122 @@
+package persistent
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestQueuePushPop(t *testing.T) {
+	var empty *Queue[int]
+	if _, _, ok := empty.Pop(); ok {
+		t.Fatal("Pop on empty queue should fail")
+	}
+	if _, ok := empty.Front(); ok {
+		t.Fatal("Front on empty queue should fail")
+	}
+
+	q := NewQueue(1, 2, 3)
+	if front, _ := q.Front(); front != 1 {
+		t.Fatalf("expected front 1, got %d", front)
+	}
+	if back, _ := q.Back(); back != 3 {
+		t.Fatalf("expected back 3, got %d", back)
+	}
+
+	x, q2, ok := q.Pop()
+	if !ok || x != 1 || q2.Len() != 2 {
+		t.Fatalf("unexpected Pop result %d len %d", x, q2.Len())
+	}
+	q3 := q2.Push(4)
+	if got := q3.ToSlice(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Fatalf("unexpected queue %v", got)
+	}
+	if got := q.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("original queue should be unchanged, got %v", got)
+	}
+
+	_, q4, _ := NewQueue(7).Pop()
+	if !q4.IsEmpty() {
+		t.Fatal("popping the last element should give an empty queue")
+	}
+}
+
+func TestQueuePersistence(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	type version struct {
+		q     *Queue[int]
+		model []int
+	}
+	versions := []version{{nil, nil}}
+	for i := 0; i < 3000; i++ {
+		v := versions[r.Intn(len(versions))]
+		if len(v.model) > 0 && r.Intn(3) == 0 {
+			x, q, ok := v.q.Pop()
+			if !ok || x != v.model[0] {
+				t.Fatalf("step %d: expected %d, got %d", i, v.model[0], x)
+			}
+			versions = append(versions, version{q, v.model[1:]})
+		} else {
+			model := append(slices.Clip(v.model), i)
+			versions = append(versions, version{v.q.Push(i), model})
+		}
+	}
+	for i, v := range versions {
+		if got := v.q.ToSlice(); !slices.Equal(got, v.model) {
+			t.Fatalf("version %d: expected %v, got %v", i, v.model, got)
+		}
+		if back, ok := v.q.Back(); ok && back != v.model[len(v.model)-1] {
+			t.Fatalf("version %d: expected back %d, got %d", i, v.model[len(v.model)-1], back)
+		}
+	}
+}
+
+func TestQueueConcurrentReaders(t *testing.T) {
+	q := NewQueue[int]()
+	for i := 0; i < 1000; i++ {
+		q = q.Push(i)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cur := q
+			for want := 0; want < 1000; want++ {
+				x, next, ok := cur.Pop()
+				if !ok || x != want {
+					t.Errorf("expected %d, got %d", want, x)
+					return
+				}
+				cur = next
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestQueueEqual(t *testing.T) {
+	a := NewQueue(1, 2, 3)
+	_, b, _ := NewQueue(0, 1, 2).Pop()
+	b = b.Push(3)
+	if !QueueEqual(a, b) {
+		t.Fatal("queues with the same elements should be equal")
+	}
+	if QueueEqual(a, b.Push(4)) || QueueEqual(a, NewQueue(1, 2, 4)) {
+		t.Fatal("different queues should not be equal")
+	}
+	if !QueueEqual(nil, NewQueue[int]()) {
+		t.Fatal("empty queues should be equal")
+	}
+	var got []int
+	for x := range a.All() {
+		if x == 3 {
+			break
+		}
+		got = append(got, x)
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("All should stop early, got %v", got)
+	}
+}
@@ -0,0 +1,107 @@
+// Package persistent 提供了不可变的持久化容器，修改操作返回新版本，旧版本保持有效并与新版本共享结构。
+// 所有类型都可以在多个 goroutine 间无锁共享。
+package persistent
+
+import (
+	"iter"
+	"slices"
+)
+
+// Stack 是一个不可变的单链表栈，Push 和 Pop 都是 O(1) 的，新版本与旧版本共享尾部。
+// nil *Stack 表示空栈。
+type Stack[T any] struct {
+	top  T
+	rest *Stack[T]
+	size int
+}
+
+// NewStack 创建一个依次压入 elements 的栈，最后一个元素位于栈顶。
+func NewStack[T any](elements ...T) *Stack[T] {
+	var s *Stack[T]
+	for _, elem := range elements {
+		s = s.Push(elem)
+	}
+	return s
+}
+
+// Len 返回栈中元素的数量。
+func (s *Stack[T]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return s.size
+}
+
+// IsEmpty 检查栈是否为空。
+func (s *Stack[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// Top 返回栈顶元素。
+// 如果栈为空，返回零值和 false。
+func (s *Stack[T]) Top() (T, bool) {
+	if s == nil {
+		var zero T
+		return zero, false
+	}
+	return s.top, true
+}
+
+// Push 返回在栈顶压入 elem 后的新栈，原栈不变。
+func (s *Stack[T]) Push(elem T) *Stack[T] {
+	return &Stack[T]{top: elem, rest: s, size: s.Len() + 1}
+}
+
+// Pop 返回栈顶元素以及弹出它之后的新栈，原栈不变。
+// 如果栈为空，返回零值、空栈和 false。
+func (s *Stack[T]) Pop() (T, *Stack[T], bool) {
+	if s == nil {
+		var zero T
+		return zero, nil, false
+	}
+	return s.top, s.rest, true
+}
+
+// All 返回一个从栈顶到栈底遍历元素的迭代器。
+func (s *Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := s; n != nil; n = n.rest {
+			if !yield(n.top) {
+				return
+			}
+		}
+	}
+}
+
+// Reverse 返回元素顺序相反的新栈，原栈的栈底成为新栈的栈顶。
+func (s *Stack[T]) Reverse() *Stack[T] {
+	var r *Stack[T]
+	for elem := range s.All() {
+		r = r.Push(elem)
+	}
+	return r
+}
+
+// ToSlice 以自底向顶的顺序返回所有元素，与 stack.Stack 的 ToSlice 一致。
+func (s *Stack[T]) ToSlice() []T {
+	result := make([]T, 0, s.Len())
+	for elem := range s.All() {
+		result = append(result, elem)
+	}
+	slices.Reverse(result)
+	return result
+}
+
+// StackEqual 判断两个栈是否拥有相同的元素，共享的尾部不会被重复比较。
+func StackEqual[T comparable](a, b *Stack[T]) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for a != b {
+		if a.top != b.top {
+			return false
+		}
+		a, b = a.rest, b.rest
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package persistent
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStackPushPop(t *testing.T) {
+	var empty *Stack[int]
+	if !empty.IsEmpty() {
+		t.Fatal("nil stack should be empty")
+	}
+	if _, _, ok := empty.Pop(); ok {
+		t.Fatal("Pop on empty stack should fail")
+	}
+
+	s1 := empty.Push(1)
+	s2 := s1.Push(2)
+	s3 := s2.Push(3)
+	if top, _ := s3.Top(); top != 3 || s3.Len() != 3 {
+		t.Fatalf("expected top 3 len 3, got %d %d", top, s3.Len())
+	}
+
+	top, rest, ok := s3.Pop()
+	if !ok || top != 3 || rest != s2 {
+		t.Fatal("Pop should return the previous version")
+	}
+	if s3.Len() != 3 || s1.Len() != 1 {
+		t.Fatal("old versions should be unchanged")
+	}
+
+	branch := s2.Push(99)
+	if got := branch.ToSlice(); !slices.Equal(got, []int{1, 2, 99}) {
+		t.Fatalf("unexpected branch %v", got)
+	}
+	if got := s3.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("original should be unaffected by branching, got %v", got)
+	}
+}
+
+func TestStackAllAndReverse(t *testing.T) {
+	s := NewStack(1, 2, 3, 4)
+	if got := slices.Collect(s.All()); !slices.Equal(got, []int{4, 3, 2, 1}) {
+		t.Fatalf("All should iterate top to bottom, got %v", got)
+	}
+	for range s.All() {
+		break
+	}
+	if got := s.Reverse().ToSlice(); !slices.Equal(got, []int{4, 3, 2, 1}) {
+		t.Fatalf("unexpected reversed stack %v", got)
+	}
+}
+
+func TestStackEqual(t *testing.T) {
+	a := NewStack(1, 2, 3)
+	b := NewStack(1, 2, 3)
+	if !StackEqual(a, b) {
+		t.Fatal("stacks with the same elements should be equal")
+	}
+	if StackEqual(a, b.Push(4)) || StackEqual(a, NewStack(1, 2, 4)) {
+		t.Fatal("different stacks should not be equal")
+	}
+	if !StackEqual(a.Push(5), a.Push(5)) {
+		t.Fatal("stacks sharing a tail should be equal")
+	}
+	if !StackEqual(NewStack[int](), nil) {
+		t.Fatal("empty stacks should be equal")
+	}
+}
@@ -0,0 +1,505 @@
+package persistent
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/Repeater11/go-template/structure/vector"
+)
+
+const (
+	// vectorBits 是每层索引使用的位数。
+	vectorBits = 5
+	// vectorWidth 是每个节点的分支数。
+	vectorWidth = 1 << vectorBits
+	// vectorMask 用于取出一层的索引。
+	vectorMask = vectorWidth - 1
+)
+
+// editToken 标识一个可变构建器，由它创建的节点可以被原地修改。
+// 包含一个字段以保证不同的 token 拥有不同的地址。
+type editToken struct{ _ byte }
+
+// vnode 是 Vector 字典树中的一个节点，内部节点保存 children，叶子节点保存 values。
+type vnode[T any] struct {
+	edit     *editToken
+	children []*vnode[T]
+	values   []T
+}
+
+// editable 返回可以在 edit 下原地修改的节点：属于 edit 的节点直接返回，否则返回一个副本。
+// edit 为 nil 时总是返回副本。
+func (n *vnode[T]) editable(edit *editToken) *vnode[T] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &vnode[T]{
+		edit:     edit,
+		children: slices.Clone(n.children),
+		values:   slices.Clone(n.values),
+	}
+}
+
+// Vector 是一个不可变的动态数组，使用 Clojure 风格的 32 路位分区字典树实现。
+// 最后不足 32 个的元素保存在单独的尾部中，因此 Append 和 Pop 的均摊时间复杂度为 O(1)，
+// Get 和 Set 的时间复杂度为 O(log32 n)，修改操作只复制从根到目标叶子路径上的节点。
+// nil *Vector 表示空数组。
+type Vector[T any] struct {
+	count int
+	shift uint
+	root  *vnode[T]
+	tail  []T
+}
+
+// NewVector 创建一个包含 elements 的 Vector。
+func NewVector[T any](elements ...T) *Vector[T] {
+	t := (*Vector[T])(nil).Transient()
+	t.Append(elements...)
+	return t.Persistent()
+}
+
+// FromVector 创建一个与 vector.Vector 拥有相同元素的 Vector。
+func FromVector[T any](v *vector.Vector[T]) *Vector[T] {
+	t := (*Vector[T])(nil).Transient()
+	for i := 0; i < v.Len(); i++ {
+		t.Append(v.At(i))
+	}
+	return t.Persistent()
+}
+
+// ToVector 返回一个拥有相同元素的 vector.Vector。
+func (v *Vector[T]) ToVector() *vector.Vector[T] {
+	result := vector.NewVector[T]()
+	result.Reserve(v.Len())
+	for chunk := range v.chunks() {
+		result.PushBack(chunk...)
+	}
+	return result
+}
+
+// Len 返回 Vector 中元素的数量。
+func (v *Vector[T]) Len() int {
+	if v == nil {
+		return 0
+	}
+	return v.count
+}
+
+// IsEmpty 检查 Vector 是否为空。
+func (v *Vector[T]) IsEmpty() bool {
+	return v.Len() == 0
+}
+
+// At 返回指定索引处的元素，索引越界时会引发 panic。
+func (v *Vector[T]) At(index int) T {
+	if index < 0 || index >= v.Len() {
+		panic("persistent: index out of range")
+	}
+	return v.leafFor(index)[index&vectorMask]
+}
+
+// Get 安全地返回指定索引处的元素。
+// 如果索引无效，返回零值和 false。
+func (v *Vector[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= v.Len() {
+		var zero T
+		return zero, false
+	}
+	return v.leafFor(index)[index&vectorMask], true
+}
+
+// Front 返回第一个元素。
+// 如果 Vector 为空，返回零值和 false。
+func (v *Vector[T]) Front() (T, bool) {
+	return v.Get(0)
+}
+
+// Back 返回最后一个元素。
+// 如果 Vector 为空，返回零值和 false。
+func (v *Vector[T]) Back() (T, bool) {
+	return v.Get(v.Len() - 1)
+}
+
+// Append 返回在末尾添加 elements 后的新 Vector，原 Vector 不变。
+// 添加多个元素时内部使用 Transient，避免复制中间版本。
+func (v *Vector[T]) Append(elements ...T) *Vector[T] {
+	switch len(elements) {
+	case 0:
+		return v
+	case 1:
+		next := v.copy()
+		next.append(elements[0], nil)
+		return next
+	}
+	t := v.Transient()
+	t.Append(elements...)
+	return t.Persistent()
+}
+
+// Set 返回将指定索引处的元素设置为 value 后的新 Vector，原 Vector 不变。
+// 如果索引无效，返回原 Vector 和 false。
+func (v *Vector[T]) Set(index int, value T) (*Vector[T], bool) {
+	if index < 0 || index >= v.Len() {
+		return v, false
+	}
+	next := v.copy()
+	next.set(index, value, nil)
+	return next, true
+}
+
+// Pop 返回最后一个元素以及移除它之后的新 Vector，原 Vector 不变。
+// 如果 Vector 为空，返回零值、空 Vector 和 false。
+func (v *Vector[T]) Pop() (T, *Vector[T], bool) {
+	if v.IsEmpty() {
+		var zero T
+		return zero, nil, false
+	}
+	next := v.copy()
+	elem := next.pop(nil)
+	return elem, next, true
+}
+
+// Slice 返回包含 [lo, hi) 范围内元素的新 Vector，元素会被复制而不与原 Vector 共享。
+// 如果范围无效，返回 nil 和 false。
+func (v *Vector[T]) Slice(lo, hi int) (*Vector[T], bool) {
+	if lo < 0 || hi > v.Len() || lo > hi {
+		return nil, false
+	}
+	t := (*Vector[T])(nil).Transient()
+	for i := lo; i < hi; i++ {
+		t.Append(v.leafFor(i)[i&vectorMask])
+	}
+	return t.Persistent(), true
+}
+
+// All 返回一个按索引升序遍历元素的迭代器。
+func (v *Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for chunk := range v.chunks() {
+			for _, elem := range chunk {
+				if !yield(i, elem) {
+					return
+				}
+				i++
+			}
+		}
+	}
+}
+
+// Backward 返回一个按索引降序遍历元素的迭代器。
+func (v *Vector[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := v.Len() - 1; i >= 0; {
+			leaf := v.leafFor(i)
+			for j := i & vectorMask; j >= 0; j-- {
+				if !yield(i, leaf[j]) {
+					return
+				}
+				i--
+			}
+		}
+	}
+}
+
+// Values 返回一个按索引升序遍历元素值的迭代器。
+func (v *Vector[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, elem := range v.All() {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice 将 Vector 转换为一个切片并返回。
+func (v *Vector[T]) ToSlice() []T {
+	result := make([]T, 0, v.Len())
+	for chunk := range v.chunks() {
+		result = append(result, chunk...)
+	}
+	return result
+}
+
+// Transient 返回一个以当前内容为初始值的可变构建器，原 Vector 不受后续修改影响。
+func (v *Vector[T]) Transient() *VectorTransient[T] {
+	t := &VectorTransient[T]{edit: &editToken{}}
+	if v != nil {
+		t.v = *v
+	}
+	if t.v.root == nil {
+		t.v.root = &vnode[T]{}
+		t.v.shift = vectorBits
+	}
+	// 尾部可能与其他版本共享，复制一份以便原地追加
+	tail := make([]T, len(t.v.tail), vectorWidth)
+	copy(tail, t.v.tail)
+	t.v.tail = tail
+	return t
+}
+
+// VectorEqual 判断两个 Vector 是否按相同顺序拥有相同的元素。
+func VectorEqual[T comparable](a, b *Vector[T]) bool {
+	if a == b {
+		return true
+	}
+	if a.Len() != b.Len() {
+		return false
+	}
+	for i, x := range a.All() {
+		if b.leafFor(i)[i&vectorMask] != x {
+			return false
+		}
+	}
+	return true
+}
+
+// VectorTransient 是 Vector 的可变构建器，用于批量修改时避免每次操作都复制路径。
+// VectorTransient 只会原地修改自己创建的节点，与原 Vector 共享的节点在首次修改时被复制。
+// 调用 Persistent 之后 VectorTransient 不能再使用。VectorTransient 不是并发安全的。
+type VectorTransient[T any] struct {
+	v    Vector[T]
+	edit *editToken
+}
+
+// Len 返回构建器中元素的数量。
+func (t *VectorTransient[T]) Len() int {
+	return t.v.count
+}
+
+// Get 安全地返回指定索引处的元素。
+// 如果索引无效，返回零值和 false。
+func (t *VectorTransient[T]) Get(index int) (T, bool) {
+	t.ensureEditable()
+	return t.v.Get(index)
+}
+
+// Append 在末尾添加一个或多个元素。
+func (t *VectorTransient[T]) Append(elements ...T) {
+	t.ensureEditable()
+	for _, elem := range elements {
+		t.v.append(elem, t.edit)
+	}
+}
+
+// Set 设置指定索引处的元素的值。
+// 如果索引无效返回 false。
+func (t *VectorTransient[T]) Set(index int, value T) bool {
+	t.ensureEditable()
+	if index < 0 || index >= t.v.count {
+		return false
+	}
+	t.v.set(index, value, t.edit)
+	return true
+}
+
+// Pop 移除并返回最后一个元素。
+// 如果构建器为空，返回零值和 false。
+func (t *VectorTransient[T]) Pop() (T, bool) {
+	t.ensureEditable()
+	if t.v.count == 0 {
+		var zero T
+		return zero, false
+	}
+	return t.v.pop(t.edit), true
+}
+
+// Persistent 返回包含构建结果的不可变 Vector，之后 VectorTransient 不能再使用。
+func (t *VectorTransient[T]) Persistent() *Vector[T] {
+	t.ensureEditable()
+	t.edit = nil
+	if t.v.count == 0 {
+		return nil
+	}
+	v := t.v
+	v.tail = slices.Clip(v.tail)
+	return &v
+}
+
+// ensureEditable 检查构建器是否仍然可用。
+func (t *VectorTransient[T]) ensureEditable() {
+	if t.edit == nil {
+		panic("persistent: transient used after Persistent")
+	}
+}
+
+// copy 返回 Vector 的浅拷贝，空 Vector 会被初始化为可以直接修改的结构。
+func (v *Vector[T]) copy() *Vector[T] {
+	if v == nil || v.root == nil {
+		return &Vector[T]{shift: vectorBits, root: &vnode[T]{}}
+	}
+	next := *v
+	return &next
+}
+
+// tailOffset 返回尾部第一个元素的索引。
+func (v *Vector[T]) tailOffset() int {
+	return v.count - len(v.tail)
+}
+
+// leafFor 返回包含指定索引的叶子元素数组，调用者需保证索引有效。
+func (v *Vector[T]) leafFor(index int) []T {
+	if index >= v.tailOffset() {
+		return v.tail
+	}
+	n := v.root
+	for level := v.shift; level > 0; level -= vectorBits {
+		n = n.children[(index>>level)&vectorMask]
+	}
+	return n.values
+}
+
+// chunks 返回一个按顺序遍历所有叶子和尾部的迭代器。
+func (v *Vector[T]) chunks() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if v.IsEmpty() {
+			return
+		}
+		for i := 0; i < v.tailOffset(); i += vectorWidth {
+			if !yield(v.leafFor(i)) {
+				return
+			}
+		}
+		yield(v.tail)
+	}
+}
+
+// append 在末尾添加一个元素，edit 为 nil 时复制所有被修改的节点和尾部。
+func (v *Vector[T]) append(elem T, edit *editToken) {
+	if len(v.tail) < vectorWidth {
+		if edit == nil {
+			v.tail = append(slices.Clip(v.tail), elem)
+		} else {
+			v.tail = append(v.tail, elem)
+		}
+		v.count++
+		return
+	}
+
+	leaf := &vnode[T]{edit: edit, values: v.tail}
+	if (v.count >> vectorBits) > (1 << v.shift) {
+		// 根节点已满，增加一层
+		v.root = &vnode[T]{edit: edit, children: []*vnode[T]{v.root, newPath(v.shift, leaf, edit)}}
+		v.shift += vectorBits
+	} else {
+		v.root = v.pushTail(v.shift, v.root, leaf, edit)
+	}
+	if edit == nil {
+		v.tail = []T{elem}
+	} else {
+		v.tail = make([]T, 1, vectorWidth)
+		v.tail[0] = elem
+	}
+	v.count++
+}
+
+// pushTail 将已满的尾部作为叶子插入到以 n 为根、位于 level 层的子树中。
+func (v *Vector[T]) pushTail(level uint, n, leaf *vnode[T], edit *editToken) *vnode[T] {
+	result := n.editable(edit)
+	sub := ((v.count - 1) >> level) & vectorMask
+	var child *vnode[T]
+	if level == vectorBits {
+		child = leaf
+	} else if sub < len(n.children) {
+		child = v.pushTail(level-vectorBits, n.children[sub], leaf, edit)
+	} else {
+		child = newPath(level-vectorBits, leaf, edit)
+	}
+	if sub < len(result.children) {
+		result.children[sub] = child
+	} else {
+		result.children = append(result.children, child)
+	}
+	return result
+}
+
+// newPath 创建一条从 level 层到叶子的单链路径。
+func newPath[T any](level uint, leaf *vnode[T], edit *editToken) *vnode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &vnode[T]{edit: edit, children: []*vnode[T]{newPath(level-vectorBits, leaf, edit)}}
+}
+
+// set 设置指定索引处的元素，edit 为 nil 时复制路径上的节点。
+func (v *Vector[T]) set(index int, value T, edit *editToken) {
+	if index >= v.tailOffset() {
+		if edit == nil {
+			v.tail = slices.Clone(v.tail)
+		}
+		v.tail[index&vectorMask] = value
+		return
+	}
+	v.root = setIn(v.shift, v.root, index, value, edit)
+}
+
+// setIn 在以 n 为根、位于 level 层的子树中设置指定索引处的元素。
+func setIn[T any](level uint, n *vnode[T], index int, value T, edit *editToken) *vnode[T] {
+	result := n.editable(edit)
+	if level == 0 {
+		result.values[index&vectorMask] = value
+	} else {
+		sub := (index >> level) & vectorMask
+		result.children[sub] = setIn(level-vectorBits, n.children[sub], index, value, edit)
+	}
+	return result
+}
+
+// pop 移除并返回最后一个元素，调用者需保证 Vector 非空。
+func (v *Vector[T]) pop(edit *editToken) T {
+	elem := v.tail[len(v.tail)-1]
+	if len(v.tail) > 1 || v.count == 1 {
+		var zero T
+		if edit != nil {
+			v.tail[len(v.tail)-1] = zero // 清零防止内存泄漏
+		}
+		v.tail = v.tail[:len(v.tail)-1]
+		v.count--
+		return elem
+	}
+
+	// 尾部只剩一个元素，将最后一个叶子移到尾部
+	newTail := v.leafFor(v.count - 2)
+	if edit != nil {
+		tail := make([]T, len(newTail), vectorWidth)
+		copy(tail, newTail)
+		newTail = tail
+	}
+	root := v.popTail(v.shift, v.root, edit)
+	if root == nil {
+		root = &vnode[T]{edit: edit}
+	}
+	if v.shift > vectorBits && len(root.children) == 1 {
+		root = root.children[0]
+		v.shift -= vectorBits
+	}
+	v.root = root
+	v.tail = newTail
+	v.count--
+	return elem
+}
+
+// popTail 从以 n 为根、位于 level 层的子树中移除最后一个叶子，子树变空时返回 nil。
+func (v *Vector[T]) popTail(level uint, n *vnode[T], edit *editToken) *vnode[T] {
+	sub := ((v.count - 2) >> level) & vectorMask
+	if level > vectorBits {
+		child := v.popTail(level-vectorBits, n.children[sub], edit)
+		if child == nil && sub == 0 {
+			return nil
+		}
+		result := n.editable(edit)
+		if child == nil {
+			result.children = result.children[:sub]
+		} else {
+			result.children[sub] = child
+		}
+		return result
+	}
+	if sub == 0 {
+		return nil
+	}
+	result := n.editable(edit)
+	result.children = result.children[:sub]
+	return result
+}
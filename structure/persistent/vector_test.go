@@ -0,0 +1,264 @@
+package persistent
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/Repeater11/go-template/structure/vector"
+)
+
+func checkVector(t *testing.T, v *Vector[int], want []int) {
+	t.Helper()
+	if v.Len() != len(want) {
+		t.Fatalf("expected len %d, got %d", len(want), v.Len())
+	}
+	if got := v.ToSlice(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i, x := range want {
+		if got, ok := v.Get(i); !ok || got != x {
+			t.Fatalf("Get(%d) expected %d, got %d", i, x, got)
+		}
+	}
+}
+
+func TestVectorAppendGet(t *testing.T) {
+	var v *Vector[int]
+	var want []int
+	// 覆盖尾部、单层、两层和三层字典树
+	for i := 0; i < 40000; i++ {
+		v = v.Append(i)
+		want = append(want, i)
+	}
+	checkVector(t, v, want)
+	if _, ok := v.Get(-1); ok {
+		t.Fatal("Get with negative index should fail")
+	}
+	if _, ok := v.Get(v.Len()); ok {
+		t.Fatal("Get past the end should fail")
+	}
+	if front, _ := v.Front(); front != 0 {
+		t.Fatalf("expected front 0, got %d", front)
+	}
+	if back, _ := v.Back(); back != 39999 {
+		t.Fatalf("expected back 39999, got %d", back)
+	}
+}
+
+func TestVectorPersistence(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	type version struct {
+		v     *Vector[int]
+		model []int
+	}
+	versions := []version{{nil, nil}}
+	for i := 0; i < 5000; i++ {
+		cur := versions[r.Intn(len(versions))]
+		model := slices.Clone(cur.model)
+		var next *Vector[int]
+		switch op := r.Intn(10); {
+		case op < 6:
+			n := r.Intn(70) + 1
+			batch := make([]int, n)
+			for j := range batch {
+				batch[j] = r.Int()
+			}
+			next = cur.v.Append(batch...)
+			model = append(model, batch...)
+		case op < 8 && len(model) > 0:
+			idx := r.Intn(len(model))
+			var ok bool
+			next, ok = cur.v.Set(idx, -i)
+			if !ok {
+				t.Fatal("Set with valid index should succeed")
+			}
+			model[idx] = -i
+		default:
+			x, popped, ok := cur.v.Pop()
+			if ok != (len(model) > 0) {
+				t.Fatalf("unexpected Pop result %v for len %d", ok, len(model))
+			}
+			if ok {
+				if x != model[len(model)-1] {
+					t.Fatalf("expected popped %d, got %d", model[len(model)-1], x)
+				}
+				model = model[:len(model)-1]
+			}
+			next = popped
+		}
+		versions = append(versions, version{next, model})
+	}
+	for _, v := range versions {
+		checkVector(t, v.v, v.model)
+	}
+}
+
+func TestVectorPopAcrossLevels(t *testing.T) {
+	const n = 32*32 + 64
+	v := NewVector[int]()
+	for i := 0; i < n; i++ {
+		v = v.Append(i)
+	}
+	for i := n - 1; i >= 0; i-- {
+		x, next, ok := v.Pop()
+		if !ok || x != i || next.Len() != i {
+			t.Fatalf("expected %d with len %d, got %d len %d", i, i, x, next.Len())
+		}
+		v = next
+	}
+	if _, _, ok := v.Pop(); ok {
+		t.Fatal("Pop on empty vector should fail")
+	}
+	v = v.Append(7)
+	checkVector(t, v, []int{7})
+}
+
+func TestVectorSetOutOfRange(t *testing.T) {
+	v := NewVector(1, 2, 3)
+	if same, ok := v.Set(3, 0); ok || same != v {
+		t.Fatal("Set out of range should fail and return the original")
+	}
+}
+
+func TestVectorSlice(t *testing.T) {
+	v := NewVector[int]()
+	for i := 0; i < 100; i++ {
+		v = v.Append(i)
+	}
+	s, ok := v.Slice(30, 70)
+	if !ok {
+		t.Fatal("Slice with valid range should succeed")
+	}
+	want := make([]int, 40)
+	for i := range want {
+		want[i] = 30 + i
+	}
+	checkVector(t, s, want)
+	if _, ok := v.Slice(50, 40); ok {
+		t.Fatal("Slice with lo > hi should fail")
+	}
+	if _, ok := v.Slice(0, 101); ok {
+		t.Fatal("Slice past the end should fail")
+	}
+}
+
+func TestVectorIterators(t *testing.T) {
+	v := NewVector[int]()
+	for i := 0; i < 100; i++ {
+		v = v.Append(i * 2)
+	}
+	for i, x := range v.All() {
+		if x != i*2 {
+			t.Fatalf("All: index %d has %d", i, x)
+		}
+	}
+	var back []int
+	for i, x := range v.Backward() {
+		if x != i*2 {
+			t.Fatalf("Backward: index %d has %d", i, x)
+		}
+		back = append(back, x)
+	}
+	if len(back) != 100 || back[0] != 198 {
+		t.Fatalf("unexpected backward iteration %v", back)
+	}
+	if got := slices.Collect(v.Values()); len(got) != 100 {
+		t.Fatalf("expected 100 values, got %d", len(got))
+	}
+	for _, x := range v.All() {
+		if x == 10 {
+			break
+		}
+	}
+}
+
+func TestTransient(t *testing.T) {
+	base := NewVector(1, 2, 3)
+	tr := base.Transient()
+	for i := 4; i <= 2000; i++ {
+		tr.Append(i)
+	}
+	tr.Set(0, 100)
+	tr.Set(500, -1)
+	if x, ok := tr.Pop(); !ok || x != 2000 {
+		t.Fatalf("expected 2000, got %d", x)
+	}
+	if x, _ := tr.Get(500); x != -1 || tr.Len() != 1999 {
+		t.Fatalf("unexpected transient state %d len %d", x, tr.Len())
+	}
+	v := tr.Persistent()
+
+	checkVector(t, base, []int{1, 2, 3})
+	if x, _ := v.Get(0); x != 100 || v.Len() != 1999 {
+		t.Fatalf("unexpected result %d len %d", x, v.Len())
+	}
+
+	// 从结果创建新的 Transient 不应影响结果
+	tr2 := v.Transient()
+	tr2.Set(500, 42)
+	tr2.Append(1)
+	if x, _ := v.Get(500); x != -1 {
+		t.Fatalf("new transient modified an existing version, got %d", x)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("using a transient after Persistent should panic")
+		}
+	}()
+	tr.Append(1)
+}
+
+func TestVectorConversion(t *testing.T) {
+	src := vector.NewVector[int]()
+	for i := 0; i < 1000; i++ {
+		src.PushBack(i)
+	}
+	v := FromVector(src)
+	if !slices.Equal(v.ToSlice(), src.ToSlice()) {
+		t.Fatal("FromVector should copy all elements")
+	}
+	back := v.ToVector()
+	if !vector.Equal(back, src) {
+		t.Fatal("ToVector should round trip")
+	}
+	back.Set(0, -1)
+	if x, _ := v.Get(0); x != 0 {
+		t.Fatal("ToVector result should not share storage")
+	}
+}
+
+func TestVectorEqual(t *testing.T) {
+	a := NewVector(1, 2, 3)
+	b := NewVector(1, 2).Append(3)
+	if !VectorEqual(a, b) {
+		t.Fatal("vectors with the same elements should be equal")
+	}
+	c, _ := b.Set(2, 4)
+	if VectorEqual(a, c) || VectorEqual(a, a.Append(4)) {
+		t.Fatal("different vectors should not be equal")
+	}
+	if !VectorEqual(nil, NewVector[int]()) {
+		t.Fatal("empty vectors should be equal")
+	}
+}
+
+func BenchmarkVectorAppend(b *testing.B) {
+	for b.Loop() {
+		var v *Vector[int]
+		for i := 0; i < 1000; i++ {
+			v = v.Append(i)
+		}
+	}
+}
+
+func BenchmarkTransientAppend(b *testing.B) {
+	for b.Loop() {
+		tr := (*Vector[int])(nil).Transient()
+		for i := 0; i < 1000; i++ {
+			tr.Append(i)
+		}
+		tr.Persistent()
+	}
+}
@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ToChan 启动一个 goroutine，按先进先出顺序取出元素并发送到返回的通道。
+// 队列为空或 ctx 被取消时关闭通道并退出 goroutine，未发送的元素保留在队列中。
+// 在通道关闭前，调用者不应再以其他方式访问该队列。
+func (q *Queue[T]) ToChan(ctx context.Context) <-chan T {
+	q.ensureDeque()
+	return q.deque.ToChan(ctx)
+}
+
+// FromChan 从 ch 中读取所有元素并按顺序放入一个新的队列。
+// 当 ch 关闭时返回队列和 nil；当 ctx 被取消时返回已读取的元素和 ctx.Err()。
+func FromChan[T any](ctx context.Context, ch <-chan T) (*Queue[T], error) {
+	q := NewQueue[T]()
+	for {
+		select {
+		case elem, ok := <-ch:
+			if !ok {
+				return q, nil
+			}
+			q.Push(elem)
+		case <-ctx.Done():
+			return q, ctx.Err()
+		}
+	}
+}
+
+// Unbounded 是一个容量无限的通道，内部使用 Queue 缓存尚未被读取的元素。
+// 向 In 发送永远不会因为读取方缓慢而阻塞（除了与内部 goroutine 交接的瞬间）。
+type Unbounded[T any] struct {
+	in   chan T
+	out  chan T
+	done chan struct{}
+	size atomic.Int64
+}
+
+// NewUnbounded 创建一个 Unbounded 并启动其内部 goroutine。
+// 关闭 In 后，缓存的元素会全部发送到 Out，然后 Out 被关闭；
+// ctx 被取消时立即关闭 Out、丢弃缓存的元素并退出内部 goroutine，不需要关闭 In。
+// 内部 goroutine 退出后不再有人读取 In，写入方应使用 Send，或者同时等待 Done：
+//
+//	select {
+//	case u.In() <- v:
+//	case <-u.Done():
+//	}
+func NewUnbounded[T any](ctx context.Context) *Unbounded[T] {
+	u := &Unbounded[T]{
+		in:   make(chan T),
+		out:  make(chan T),
+		done: make(chan struct{}),
+	}
+	go u.run(ctx)
+	return u
+}
+
+// In 返回用于写入元素的通道，写入完毕后由调用者关闭。
+func (u *Unbounded[T]) In() chan<- T {
+	return u.in
+}
+
+// Out 返回用于读取元素的通道，元素按写入顺序到达。
+func (u *Unbounded[T]) Out() <-chan T {
+	return u.out
+}
+
+// Done 返回一个在内部 goroutine 退出后关闭的通道。
+func (u *Unbounded[T]) Done() <-chan struct{} {
+	return u.done
+}
+
+// Send 向 In 写入一个元素。
+// 如果内部 goroutine 已经退出或 ctx 被取消，返回 false 且元素没有被写入。
+func (u *Unbounded[T]) Send(ctx context.Context, elem T) bool {
+	select {
+	case u.in <- elem:
+		return true
+	case <-u.done:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Len 返回当前缓存的元素数量。
+// 在并发读写期间返回的只是一个近似值。
+func (u *Unbounded[T]) Len() int {
+	return int(u.size.Load())
+}
+
+// run 在 In、内部缓冲和 Out 之间搬运元素，直到 In 关闭且缓冲清空或 ctx 被取消。
+func (u *Unbounded[T]) run(ctx context.Context) {
+	defer close(u.done)
+	defer close(u.out)
+
+	buf := NewQueue[T]()
+	in := u.in
+	for in != nil || !buf.IsEmpty() {
+		// 缓冲为空时 out 为 nil，select 不会选中发送分支
+		var out chan T
+		front, ok := buf.Front()
+		if ok {
+			out = u.out
+		}
+
+		select {
+		case elem, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			buf.Push(elem)
+			u.size.Add(1)
+		case out <- front:
+			buf.Pop()
+			u.size.Add(-1)
+		case <-ctx.Done():
+			u.size.Store(0)
+			return
+		}
+	}
+}
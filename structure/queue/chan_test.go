@@ -0,0 +1,134 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestToChan(t *testing.T) {
+	var q Queue[int]
+	for i := 0; i < 3; i++ {
+		q.Push(i)
+	}
+	i := 0
+	for v := range q.ToChan(context.Background()) {
+		if v != i {
+			t.Fatalf("expected %d, got %d", i, v)
+		}
+		i++
+	}
+	if i != 3 || !q.IsEmpty() {
+		t.Fatalf("expected 3 values and an empty queue, got %d values, len %d", i, q.Len())
+	}
+}
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan int, 4)
+	for i := 0; i < 4; i++ {
+		ch <- i
+	}
+	close(ch)
+
+	q, err := FromChan(context.Background(), ch)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if q.Len() != 4 {
+		t.Fatalf("expected length 4, got %d", q.Len())
+	}
+	if front, _ := q.Front(); front != 0 {
+		t.Fatalf("Front expected 0, got %d", front)
+	}
+}
+
+func TestFromChanCancel(t *testing.T) {
+	ch := make(chan int, 1)
+	ch <- 1
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	q, err := FromChan(ctx, ch)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expected the value read before cancellation, got length %d", q.Len())
+	}
+}
+
+func TestUnboundedOrderAndClose(t *testing.T) {
+	u := NewUnbounded[int](context.Background())
+	const n = 1000
+	// 在没有读取方的情况下写入，不应阻塞
+	for i := 0; i < n; i++ {
+		u.In() <- i
+	}
+	close(u.In())
+
+	i := 0
+	for v := range u.Out() {
+		if v != i {
+			t.Fatalf("expected %d, got %d", i, v)
+		}
+		i++
+	}
+	if i != n {
+		t.Fatalf("expected %d values, got %d", n, i)
+	}
+	if u.Len() != 0 {
+		t.Fatalf("expected empty buffer, got %d", u.Len())
+	}
+}
+
+func TestUnboundedCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := NewUnbounded[int](ctx)
+	u.In() <- 1
+	u.In() <- 2
+	cancel()
+
+	select {
+	case <-drained(u.Out()):
+	case <-time.After(time.Second):
+		t.Fatal("Out should be closed after cancellation")
+	}
+}
+
+func TestUnboundedCancelWithoutClosingIn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := NewUnbounded[int](ctx)
+	if !u.Send(context.Background(), 1) {
+		t.Fatal("Send before cancellation should succeed")
+	}
+	cancel()
+
+	select {
+	case <-u.Done():
+	case <-time.After(time.Second):
+		t.Fatal("internal goroutine should exit after cancellation without closing In")
+	}
+	if u.Send(context.Background(), 2) {
+		t.Fatal("Send after the goroutine exited should fail")
+	}
+	select {
+	case u.In() <- 3:
+		t.Fatal("nobody should receive from In after the goroutine exited")
+	case <-u.Done():
+	}
+	if u.Len() != 0 {
+		t.Fatalf("buffer should be discarded after cancellation, got %d", u.Len())
+	}
+}
+
+// drained 读取 ch 直到关闭，然后关闭返回的通道。
+func drained[T any](ch <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range ch {
+		}
+		close(done)
+	}()
+	return done
+}
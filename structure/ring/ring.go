@@ -0,0 +1,245 @@
+// Package ring 提供了基于单个预分配切片的泛型定长环形缓冲区实现。
+package ring
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Mode 决定环形缓冲区已满时写入操作的行为。
+type Mode int
+
+const (
+	// Reject 表示缓冲区已满时拒绝写入，写入方法返回 false。
+	Reject Mode = iota
+	// Overwrite 表示缓冲区已满时覆盖另一端最旧的元素。
+	Overwrite
+)
+
+// Ring 是一个定长的泛型环形缓冲区，支持在两端插入和删除。
+// 所有元素保存在一个预分配的切片中，除 SetCap 外不会再分配内存。
+type Ring[T any] struct {
+	data []T
+	head int // 第一个元素在 data 中的下标
+	size int
+	mode Mode
+}
+
+// NewRing 创建一个指定容量的空 Ring。
+// 如果容量为负数会引发 panic。
+func NewRing[T any](capacity int, mode Mode) *Ring[T] {
+	if capacity < 0 {
+		panic(fmt.Sprintf("ring: negative capacity %d", capacity))
+	}
+	return &Ring[T]{
+		data: make([]T, capacity),
+		mode: mode,
+	}
+}
+
+// Len 返回 Ring 中元素的数量。
+func (r *Ring[T]) Len() int {
+	return r.size
+}
+
+// Cap 返回 Ring 的容量。
+func (r *Ring[T]) Cap() int {
+	return len(r.data)
+}
+
+// IsEmpty 检查 Ring 是否为空。
+func (r *Ring[T]) IsEmpty() bool {
+	return r.size == 0
+}
+
+// IsFull 检查 Ring 是否已满。
+func (r *Ring[T]) IsFull() bool {
+	return r.size == len(r.data)
+}
+
+// Clear 移除 Ring 中的所有元素，容量保持不变。
+func (r *Ring[T]) Clear() {
+	clear(r.data)
+	r.head = 0
+	r.size = 0
+}
+
+// PushBack 在 Ring 的尾部添加一个元素。
+// 缓冲区已满时，Overwrite 模式会覆盖头部元素，Reject 模式返回 false。
+func (r *Ring[T]) PushBack(elem T) bool {
+	if r.IsFull() {
+		if r.mode == Reject || len(r.data) == 0 {
+			return false
+		}
+		// 尾部的下一个位置正是头部，覆盖后头部后移
+		r.data[r.head] = elem
+		r.head = r.index(1)
+		return true
+	}
+	r.data[r.index(r.size)] = elem
+	r.size++
+	return true
+}
+
+// PushFront 在 Ring 的头部添加一个元素。
+// 缓冲区已满时，Overwrite 模式会覆盖尾部元素，Reject 模式返回 false。
+func (r *Ring[T]) PushFront(elem T) bool {
+	if r.IsFull() {
+		if r.mode == Reject || len(r.data) == 0 {
+			return false
+		}
+		// 头部的前一个位置正是尾部，覆盖后头部前移
+		r.head = r.index(-1)
+		r.data[r.head] = elem
+		return true
+	}
+	r.head = r.index(-1)
+	r.data[r.head] = elem
+	r.size++
+	return true
+}
+
+// PopFront 从 Ring 的头部移除并返回一个元素。
+// 如果 Ring 为空，返回零值和 false。
+func (r *Ring[T]) PopFront() (T, bool) {
+	var zero T
+	if r.IsEmpty() {
+		return zero, false
+	}
+	elem := r.data[r.head]
+	r.data[r.head] = zero // 清零防止内存泄漏
+	r.head = r.index(1)
+	r.size--
+	return elem, true
+}
+
+// PopBack 从 Ring 的尾部移除并返回一个元素。
+// 如果 Ring 为空，返回零值和 false。
+func (r *Ring[T]) PopBack() (T, bool) {
+	var zero T
+	if r.IsEmpty() {
+		return zero, false
+	}
+	i := r.index(r.size - 1)
+	elem := r.data[i]
+	r.data[i] = zero // 清零防止内存泄漏
+	r.size--
+	return elem, true
+}
+
+// Front 返回 Ring 头部的元素但不移除它。
+// 如果 Ring 为空，返回零值和 false。
+func (r *Ring[T]) Front() (T, bool) {
+	return r.Get(0)
+}
+
+// Back 返回 Ring 尾部的元素但不移除它。
+// 如果 Ring 为空，返回零值和 false。
+func (r *Ring[T]) Back() (T, bool) {
+	return r.Get(r.size - 1)
+}
+
+// At 返回指定索引处的元素。
+// 索引从 0 开始，不检查是否超过 Len，越界的结果是未定义的。
+func (r *Ring[T]) At(index int) T {
+	return r.data[r.index(index)]
+}
+
+// Get 安全地返回指定索引处的元素。
+// 如果索引无效，返回零值和 false。
+func (r *Ring[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= r.size {
+		var zero T
+		return zero, false
+	}
+	return r.At(index), true
+}
+
+// Set 设置指定索引处的元素的值。
+// 如果索引无效返回 false。
+func (r *Ring[T]) Set(index int, value T) bool {
+	if index < 0 || index >= r.size {
+		return false
+	}
+	r.data[r.index(index)] = value
+	return true
+}
+
+// Segments 返回按顺序组成 Ring 内容的两个连续切片。
+// 第二个切片可能为空。返回的切片与 Ring 共享存储，仅在下一次修改前有效。
+func (r *Ring[T]) Segments() ([]T, []T) {
+	end := r.head + r.size
+	if end <= len(r.data) {
+		return r.data[r.head:end], r.data[:0]
+	}
+	return r.data[r.head:], r.data[:end-len(r.data)]
+}
+
+// ToSlice 将 Ring 转换为一个切片并返回。
+func (r *Ring[T]) ToSlice() []T {
+	first, second := r.Segments()
+	result := make([]T, 0, r.size)
+	result = append(result, first...)
+	return append(result, second...)
+}
+
+// All 返回一个从头到尾遍历索引和元素的迭代器。
+func (r *Ring[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < r.size; i++ {
+			if !yield(i, r.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 返回一个从尾到头遍历索引和元素的迭代器。
+func (r *Ring[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := r.size - 1; i >= 0; i-- {
+			if !yield(i, r.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+// SetCap 调整 Ring 的容量并保持元素顺序。
+// 如果新容量小于当前元素数量，丢弃头部最旧的元素。
+// 如果新容量为负数会引发 panic。
+func (r *Ring[T]) SetCap(newCap int) {
+	if newCap < 0 {
+		panic(fmt.Sprintf("ring: negative capacity %d", newCap))
+	}
+	drop := max(r.size-newCap, 0)
+	data := make([]T, newCap)
+	for i := drop; i < r.size; i++ {
+		data[i-drop] = r.At(i)
+	}
+	r.data = data
+	r.head = 0
+	r.size -= drop
+}
+
+// Clone 创建并返回 Ring 的一个副本，容量和模式保持不变。
+func (r *Ring[T]) Clone() *Ring[T] {
+	return &Ring[T]{
+		data: append([]T{}, r.data...),
+		head: r.head,
+		size: r.size,
+		mode: r.mode,
+	}
+}
+
+// index 将相对于头部的偏移转换为 data 中的下标，offset 可以为 -1。
+func (r *Ring[T]) index(offset int) int {
+	i := r.head + offset
+	switch {
+	case i < 0:
+		i += len(r.data)
+	case i >= len(r.data):
+		i -= len(r.data)
+	}
+	return i
+}
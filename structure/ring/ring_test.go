@@ -0,0 +1,191 @@
+package ring
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewRing(t *testing.T) {
+	r := NewRing[int](4, Reject)
+	if r.Len() != 0 || r.Cap() != 4 || !r.IsEmpty() || r.IsFull() {
+		t.Fatalf("unexpected new ring state: len %d cap %d", r.Len(), r.Cap())
+	}
+	if _, ok := r.PopFront(); ok {
+		t.Fatal("PopFront on empty ring should fail")
+	}
+	if _, ok := r.Back(); ok {
+		t.Fatal("Back on empty ring should fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("negative capacity should panic")
+		}
+	}()
+	NewRing[int](-1, Reject)
+}
+
+func TestRejectMode(t *testing.T) {
+	r := NewRing[int](3, Reject)
+	for i := 1; i <= 3; i++ {
+		if !r.PushBack(i) {
+			t.Fatalf("PushBack(%d) should succeed", i)
+		}
+	}
+	if !r.IsFull() {
+		t.Fatal("ring should be full")
+	}
+	if r.PushBack(4) || r.PushFront(0) {
+		t.Fatal("pushing into a full ring should be rejected")
+	}
+	if got := r.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+}
+
+func TestOverwriteMode(t *testing.T) {
+	r := NewRing[int](3, Overwrite)
+	for i := 1; i <= 5; i++ {
+		r.PushBack(i)
+	}
+	if got := r.ToSlice(); !slices.Equal(got, []int{3, 4, 5}) {
+		t.Fatalf("PushBack should overwrite the oldest, got %v", got)
+	}
+
+	r.PushFront(0)
+	if got := r.ToSlice(); !slices.Equal(got, []int{0, 3, 4}) {
+		t.Fatalf("PushFront should overwrite the back, got %v", got)
+	}
+
+	zero := NewRing[int](0, Overwrite)
+	if zero.PushBack(1) || zero.PushFront(1) || zero.Len() != 0 {
+		t.Fatal("zero capacity ring should reject all pushes")
+	}
+}
+
+func TestPushPopBothEnds(t *testing.T) {
+	r := NewRing[int](5, Reject)
+	r.PushBack(2)
+	r.PushBack(3)
+	r.PushFront(1)
+	r.PushFront(0)
+
+	if v, ok := r.PopFront(); !ok || v != 0 {
+		t.Fatalf("PopFront expected 0, got %d", v)
+	}
+	if v, ok := r.PopBack(); !ok || v != 3 {
+		t.Fatalf("PopBack expected 3, got %d", v)
+	}
+	if front, _ := r.Front(); front != 1 {
+		t.Fatalf("Front expected 1, got %d", front)
+	}
+	if back, _ := r.Back(); back != 2 {
+		t.Fatalf("Back expected 2, got %d", back)
+	}
+}
+
+func TestAtGetSet(t *testing.T) {
+	r := NewRing[string](3, Overwrite)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		r.PushBack(s)
+	}
+	if r.At(0) != "b" || r.At(2) != "d" {
+		t.Fatalf("At unexpected values %s %s", r.At(0), r.At(2))
+	}
+	if v, ok := r.Get(1); !ok || v != "c" {
+		t.Fatalf("Get(1) expected c, got %s", v)
+	}
+	if _, ok := r.Get(3); ok {
+		t.Fatal("Get out of range should fail")
+	}
+	if !r.Set(1, "x") || r.At(1) != "x" {
+		t.Fatal("Set(1) failed")
+	}
+	if r.Set(-1, "y") {
+		t.Fatal("Set out of range should fail")
+	}
+}
+
+func TestSegments(t *testing.T) {
+	r := NewRing[int](4, Overwrite)
+	for i := 0; i < 3; i++ {
+		r.PushBack(i)
+	}
+	first, second := r.Segments()
+	if !slices.Equal(first, []int{0, 1, 2}) || len(second) != 0 {
+		t.Fatalf("unexpected segments %v %v", first, second)
+	}
+
+	r.PushBack(3)
+	r.PushBack(4)
+	r.PushBack(5)
+	first, second = r.Segments()
+	if !slices.Equal(first, []int{2, 3}) || !slices.Equal(second, []int{4, 5}) {
+		t.Fatalf("unexpected wrapped segments %v %v", first, second)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	r := NewRing[int](3, Overwrite)
+	for i := 0; i < 5; i++ {
+		r.PushBack(i)
+	}
+	var idx, vals []int
+	for i, v := range r.All() {
+		idx = append(idx, i)
+		vals = append(vals, v)
+	}
+	if !slices.Equal(idx, []int{0, 1, 2}) || !slices.Equal(vals, []int{2, 3, 4}) {
+		t.Fatalf("All unexpected result %v %v", idx, vals)
+	}
+	vals = vals[:0]
+	for _, v := range r.Backward() {
+		vals = append(vals, v)
+	}
+	if !slices.Equal(vals, []int{4, 3, 2}) {
+		t.Fatalf("Backward unexpected result %v", vals)
+	}
+}
+
+func TestSetCap(t *testing.T) {
+	r := NewRing[int](4, Overwrite)
+	for i := 0; i < 6; i++ {
+		r.PushBack(i)
+	}
+
+	r.SetCap(6)
+	if r.Cap() != 6 || !slices.Equal(r.ToSlice(), []int{2, 3, 4, 5}) {
+		t.Fatalf("growing should preserve order, got %v", r.ToSlice())
+	}
+	r.PushBack(6)
+	r.PushBack(7)
+	if !r.IsFull() {
+		t.Fatal("ring should be full after filling the new capacity")
+	}
+
+	r.SetCap(3)
+	if r.Cap() != 3 || !slices.Equal(r.ToSlice(), []int{5, 6, 7}) {
+		t.Fatalf("shrinking should keep the newest elements, got %v", r.ToSlice())
+	}
+}
+
+func TestClearAndClone(t *testing.T) {
+	r := NewRing[int](3, Reject)
+	r.PushBack(1)
+	r.PushBack(2)
+	c := r.Clone()
+	r.Clear()
+	if !r.IsEmpty() || r.Cap() != 3 {
+		t.Fatal("Clear should empty the ring and keep its capacity")
+	}
+	if !slices.Equal(c.ToSlice(), []int{1, 2}) {
+		t.Fatalf("clone should be independent, got %v", c.ToSlice())
+	}
+}
+
+func BenchmarkPushBackOverwrite(b *testing.B) {
+	r := NewRing[int](1024, Overwrite)
+	for i := 0; i < b.N; i++ {
+		r.PushBack(i)
+	}
+}
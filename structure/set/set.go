@@ -0,0 +1,250 @@
+// Package set 提供了基于哈希表的泛型集合实现。
+package set
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/Repeater11/go-template/structure/deque"
+	"github.com/Repeater11/go-template/structure/vector"
+)
+
+// Set 是一个泛型哈希集合，元素无序且不重复。
+// 零值 Set 可以直接使用。
+type Set[T comparable] struct {
+	data map[T]struct{}
+}
+
+// NewSet 创建一个空的 Set。
+// 可选地，可以传入初始元素来填充 Set。
+func NewSet[T comparable](elements ...T) *Set[T] {
+	s := &Set[T]{
+		data: make(map[T]struct{}, len(elements)),
+	}
+	s.Add(elements...)
+	return s
+}
+
+// NewSetFromSlice 从给定的切片创建一个 Set。
+func NewSetFromSlice[T comparable](slice []T) *Set[T] {
+	return NewSet(slice...)
+}
+
+// NewSetFromVector 从给定的 Vector 创建一个 Set。
+func NewSetFromVector[T comparable](v *vector.Vector[T]) *Set[T] {
+	s := &Set[T]{
+		data: make(map[T]struct{}, v.Len()),
+	}
+	for i := 0; i < v.Len(); i++ {
+		s.data[v.At(i)] = struct{}{}
+	}
+	return s
+}
+
+// NewSetFromDeque 从给定的 Deque 创建一个 Set。
+func NewSetFromDeque[T comparable](d *deque.Deque[T]) *Set[T] {
+	s := &Set[T]{
+		data: make(map[T]struct{}, d.Len()),
+	}
+	for i := 0; i < d.Len(); i++ {
+		s.data[d.At(i)] = struct{}{}
+	}
+	return s
+}
+
+// NewSetFromSeq 从给定的迭代器创建一个 Set。
+func NewSetFromSeq[T comparable](seq iter.Seq[T]) *Set[T] {
+	s := NewSet[T]()
+	for elem := range seq {
+		s.data[elem] = struct{}{}
+	}
+	return s
+}
+
+// Len 返回 Set 中元素的数量。
+func (s *Set[T]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.data)
+}
+
+// IsEmpty 检查 Set 是否为空。
+func (s *Set[T]) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+// Add 向 Set 中添加一个或多个元素，已存在的元素会被忽略。
+func (s *Set[T]) Add(elements ...T) {
+	if s == nil {
+		return
+	}
+	s.ensureMap()
+	for _, elem := range elements {
+		s.data[elem] = struct{}{}
+	}
+}
+
+// Remove 从 Set 中移除一个或多个元素，不存在的元素会被忽略。
+func (s *Set[T]) Remove(elements ...T) {
+	if s == nil || s.data == nil {
+		return
+	}
+	for _, elem := range elements {
+		delete(s.data, elem)
+	}
+}
+
+// Contains 检查 Set 是否包含指定的元素。
+func (s *Set[T]) Contains(elem T) bool {
+	if s == nil || s.data == nil {
+		return false
+	}
+	_, ok := s.data[elem]
+	return ok
+}
+
+// Clear 移除 Set 中的所有元素。
+func (s *Set[T]) Clear() {
+	if s == nil || s.data == nil {
+		return
+	}
+	clear(s.data)
+}
+
+// Union 返回一个新的 Set，包含 s 与 other 中的所有元素。
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := &Set[T]{
+		data: make(map[T]struct{}, s.Len()+other.Len()),
+	}
+	for elem := range s.All() {
+		result.data[elem] = struct{}{}
+	}
+	for elem := range other.All() {
+		result.data[elem] = struct{}{}
+	}
+	return result
+}
+
+// Intersection 返回一个新的 Set，包含同时存在于 s 与 other 中的元素。
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+	result := NewSet[T]()
+	for elem := range small.All() {
+		if large.Contains(elem) {
+			result.data[elem] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference 返回一个新的 Set，包含存在于 s 但不存在于 other 中的元素。
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for elem := range s.All() {
+		if !other.Contains(elem) {
+			result.data[elem] = struct{}{}
+		}
+	}
+	return result
+}
+
+// SymmetricDifference 返回一个新的 Set，包含只存在于 s 或 other 其中之一的元素。
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for elem := range s.All() {
+		if !other.Contains(elem) {
+			result.data[elem] = struct{}{}
+		}
+	}
+	for elem := range other.All() {
+		if !s.Contains(elem) {
+			result.data[elem] = struct{}{}
+		}
+	}
+	return result
+}
+
+// IsSubset 检查 s 是否为 other 的子集。
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for elem := range s.All() {
+		if !other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset 检查 s 是否为 other 的超集。
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal 检查两个 Set 是否包含完全相同的元素。
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// Clone 创建并返回 Set 的一个副本。
+func (s *Set[T]) Clone() *Set[T] {
+	result := &Set[T]{
+		data: make(map[T]struct{}, s.Len()),
+	}
+	for elem := range s.All() {
+		result.data[elem] = struct{}{}
+	}
+	return result
+}
+
+// ToSlice 将 Set 转换为一个切片并返回，元素顺序不确定。
+func (s *Set[T]) ToSlice() []T {
+	result := make([]T, 0, s.Len())
+	for elem := range s.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// All 返回一个遍历 Set 中所有元素的迭代器，遍历顺序不确定。
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if s == nil {
+			return
+		}
+		for elem := range s.data {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+// ensureMap 确保内部的 map 已初始化。
+func (s *Set[T]) ensureMap() {
+	if s.data == nil {
+		s.data = make(map[T]struct{})
+	}
+}
+
+// Sorted 以升序返回 Set 中的所有元素。
+// 仅适用于实现了 cmp.Ordered 接口的类型（如 int, float64, string 等）。
+func Sorted[T cmp.Ordered](s *Set[T]) []T {
+	result := s.ToSlice()
+	slices.Sort(result)
+	return result
+}
+
+// SortedFunc 使用自定义比较函数对 Set 中的元素排序后返回。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+func SortedFunc[T comparable](s *Set[T], cmp func(a, b T) int) []T {
+	result := s.ToSlice()
+	slices.SortFunc(result, cmp)
+	return result
+}
@@ -0,0 +1,170 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/Repeater11/go-template/structure/deque"
+	"github.com/Repeater11/go-template/structure/vector"
+)
+
+func TestNewSet(t *testing.T) {
+	s := NewSet[int]()
+	if s.Len() != 0 || !s.IsEmpty() {
+		t.Fatalf("expected empty set, got len %d", s.Len())
+	}
+
+	s = NewSet(1, 2, 2, 3)
+	if s.Len() != 3 {
+		t.Fatalf("expected len 3 after dedup, got %d", s.Len())
+	}
+	for _, v := range []int{1, 2, 3} {
+		if !s.Contains(v) {
+			t.Fatalf("expected set to contain %d", v)
+		}
+	}
+}
+
+func TestConstructorsFromContainers(t *testing.T) {
+	v := vector.NewVector(3, 1, 3, 2)
+	if got := Sorted(NewSetFromVector(v)); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("NewSetFromVector expected [1 2 3], got %v", got)
+	}
+
+	d := deque.NewDeque[string]()
+	d.PushBack("b")
+	d.PushFront("a")
+	d.PushBack("a")
+	if got := Sorted(NewSetFromDeque(d)); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("NewSetFromDeque expected [a b], got %v", got)
+	}
+
+	if got := Sorted(NewSetFromSeq(slices.Values([]int{5, 4, 5}))); !slices.Equal(got, []int{4, 5}) {
+		t.Fatalf("NewSetFromSeq expected [4 5], got %v", got)
+	}
+
+	if got := Sorted(NewSetFromSlice([]int{9, 9})); !slices.Equal(got, []int{9}) {
+		t.Fatalf("NewSetFromSlice expected [9], got %v", got)
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	s := NewSet[string]()
+	s.Add("a", "b")
+	s.Add("a")
+	if s.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", s.Len())
+	}
+	s.Remove("a", "missing")
+	if s.Contains("a") || s.Len() != 1 {
+		t.Fatalf("expected only b to remain, got %v", s.ToSlice())
+	}
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Fatal("set should be empty after Clear")
+	}
+}
+
+func TestZeroValueSet(t *testing.T) {
+	var s Set[int]
+	if s.Contains(1) {
+		t.Fatal("zero value set should not contain anything")
+	}
+	s.Remove(1)
+	s.Add(1, 2)
+	if s.Len() != 2 {
+		t.Fatalf("expected len 2, got %d", s.Len())
+	}
+}
+
+func TestNilSet(t *testing.T) {
+	var s *Set[int]
+	s.Add(1)
+	s.Remove(1)
+	s.Clear()
+	if s.Contains(1) || s.Len() != 0 || !s.IsEmpty() {
+		t.Fatal("nil set should behave as an empty set")
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	tests := []struct {
+		name string
+		got  *Set[int]
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+	}
+	for _, tt := range tests {
+		if got := Sorted(tt.got); !slices.Equal(got, tt.want) {
+			t.Errorf("%s expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	if got := Sorted(a); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("set algebra should not modify operands, got %v", got)
+	}
+}
+
+func TestSubsetSupersetEqual(t *testing.T) {
+	a := NewSet(1, 2)
+	b := NewSet(1, 2, 3)
+
+	if !a.IsSubset(b) || b.IsSubset(a) {
+		t.Fatal("IsSubset returned unexpected result")
+	}
+	if !b.IsSuperset(a) || a.IsSuperset(b) {
+		t.Fatal("IsSuperset returned unexpected result")
+	}
+	if !a.IsSubset(a) || !NewSet[int]().IsSubset(a) {
+		t.Fatal("a set and the empty set should be subsets of a")
+	}
+	if !a.Equal(NewSet(2, 1)) || a.Equal(b) {
+		t.Fatal("Equal returned unexpected result")
+	}
+}
+
+func TestCloneIndependence(t *testing.T) {
+	s := NewSet(1, 2, 3)
+	c := s.Clone()
+	c.Add(4)
+	c.Remove(1)
+	if !s.Equal(NewSet(1, 2, 3)) {
+		t.Fatalf("modifying clone should not affect original, got %v", Sorted(s))
+	}
+}
+
+func TestIterators(t *testing.T) {
+	s := NewSet(1, 2, 3, 4)
+	sum := 0
+	for v := range s.All() {
+		sum += v
+	}
+	if sum != 10 {
+		t.Fatalf("expected sum 10, got %d", sum)
+	}
+
+	count := 0
+	for range s.All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatalf("iterator should stop after break, visited %d", count)
+	}
+}
+
+func TestSortedFunc(t *testing.T) {
+	s := NewSet("b", "c", "a")
+	got := SortedFunc(s, func(a, b string) int { return cmp.Compare(b, a) })
+	if !slices.Equal(got, []string{"c", "b", "a"}) {
+		t.Fatalf("SortedFunc expected [c b a], got %v", got)
+	}
+}
@@ -0,0 +1,211 @@
+// Package slidingwindow 提供了基于单调队列的滑动窗口实现，支持均摊 O(1) 的窗口最小值和最大值查询。
+package slidingwindow
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/Repeater11/go-template/structure/deque"
+)
+
+// Clock 抽象了按时间划分的窗口使用的时间源，测试中可以替换为手动推进的时钟。
+type Clock interface {
+	// Now 返回当前时间。
+	Now() time.Time
+}
+
+// systemClock 是基于 time 包的真实时钟。
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// entry 是窗口中的一个候选元素。
+type entry[T any] struct {
+	value T
+	seq   uint64 // 入窗序号
+}
+
+// SlidingWindow 是一个滑动窗口，维护最近若干个元素的最小值和最大值。
+// 按数量划分的窗口只保留最近 size 个元素；按时间划分的窗口只保留最近 d 时间内加入的元素。
+// 两种模式都可以通过 Evict 手动移出最旧的元素。
+// 内部使用两个单调 Deque 保存最小值和最大值的候选元素，
+// Push、Evict、Min 和 Max 的均摊时间复杂度都是 O(1)。
+// SlidingWindow 不是并发安全的。
+type SlidingWindow[T any] struct {
+	cmp  func(a, b T) int
+	mins *deque.Deque[entry[T]] // 值单调不减的候选
+	maxs *deque.Deque[entry[T]] // 值单调不增的候选
+
+	next   uint64 // 下一个元素的序号
+	oldest uint64 // 窗口中最旧元素的序号
+
+	size  int                     // 按数量划分时的窗口大小，按时间划分时为 0
+	span  time.Duration           // 按时间划分时的窗口长度
+	clock Clock                   // 按时间划分时的时间源
+	times *deque.Deque[time.Time] // 按时间划分时窗口中每个元素的加入时间
+}
+
+// NewSlidingWindow 创建一个最多保留最近 size 个元素的滑动窗口。
+// cmp 用于比较元素：a < b 时返回负数，a == b 时返回 0，a > b 时返回正数。
+// 如果 size 不为正数，会引发 panic。
+func NewSlidingWindow[T any](size int, cmp func(a, b T) int) *SlidingWindow[T] {
+	if size <= 0 {
+		panic("slidingwindow: size must be positive")
+	}
+	return &SlidingWindow[T]{
+		cmp:  cmp,
+		mins: deque.NewDeque[entry[T]](),
+		maxs: deque.NewDeque[entry[T]](),
+		size: size,
+	}
+}
+
+// NewOrderedSlidingWindow 创建一个元素类型为有序类型、最多保留最近 size 个元素的滑动窗口。
+func NewOrderedSlidingWindow[T cmp.Ordered](size int) *SlidingWindow[T] {
+	return NewSlidingWindow(size, cmp.Compare[T])
+}
+
+// NewTimeWindow 创建一个使用系统时钟、保留最近 d 时间内加入的元素的滑动窗口。
+// 如果 d 不为正数，会引发 panic。
+func NewTimeWindow[T any](d time.Duration, cmp func(a, b T) int) *SlidingWindow[T] {
+	return NewTimeWindowWithClock(d, cmp, systemClock{})
+}
+
+// NewTimeWindowWithClock 创建一个使用指定时钟、保留最近 d 时间内加入的元素的滑动窗口。
+// 加入时间早于或等于 Now() - d 的元素会被移出窗口。
+// 如果 d 不为正数，会引发 panic。
+func NewTimeWindowWithClock[T any](d time.Duration, cmp func(a, b T) int, clock Clock) *SlidingWindow[T] {
+	if d <= 0 {
+		panic("slidingwindow: duration must be positive")
+	}
+	return &SlidingWindow[T]{
+		cmp:   cmp,
+		mins:  deque.NewDeque[entry[T]](),
+		maxs:  deque.NewDeque[entry[T]](),
+		span:  d,
+		clock: clock,
+		times: deque.NewDeque[time.Time](),
+	}
+}
+
+// Len 返回窗口中元素的数量。
+func (w *SlidingWindow[T]) Len() int {
+	w.expire()
+	return int(w.next - w.oldest)
+}
+
+// IsEmpty 检查窗口是否为空。
+func (w *SlidingWindow[T]) IsEmpty() bool {
+	return w.Len() == 0
+}
+
+// Push 将一个元素加入窗口。
+// 按数量划分的窗口已满时会移出最旧的元素；按时间划分的窗口以当前时间作为加入时间。
+func (w *SlidingWindow[T]) Push(x T) {
+	if w.times != nil {
+		w.PushAt(x, w.clock.Now())
+		return
+	}
+	w.push(x)
+	if w.next-w.oldest > uint64(w.size) {
+		w.Evict()
+	}
+}
+
+// PushAt 以指定的加入时间将一个元素加入按时间划分的窗口。
+// 加入时间应当单调不减，早于上一个元素的时间会被视为与上一个元素相同。
+// 对按数量划分的窗口，PushAt 等同于 Push。
+func (w *SlidingWindow[T]) PushAt(x T, at time.Time) {
+	if w.times == nil {
+		w.Push(x)
+		return
+	}
+	if last, ok := w.times.Back(); ok && at.Before(last) {
+		at = last
+	}
+	w.times.PushBack(at)
+	w.push(x)
+	w.expire()
+}
+
+// Evict 移出窗口中最旧的元素。
+// 如果窗口为空，返回 false。
+func (w *SlidingWindow[T]) Evict() bool {
+	if w.oldest == w.next {
+		return false
+	}
+	if w.times != nil {
+		w.times.PopFront()
+	}
+	w.oldest++
+	if e, ok := w.mins.Front(); ok && e.seq < w.oldest {
+		w.mins.PopFront()
+	}
+	if e, ok := w.maxs.Front(); ok && e.seq < w.oldest {
+		w.maxs.PopFront()
+	}
+	return true
+}
+
+// Min 返回窗口中的最小值，有多个最小值时返回最新加入的一个。
+// 如果窗口为空，返回零值和 false。
+func (w *SlidingWindow[T]) Min() (T, bool) {
+	w.expire()
+	e, ok := w.mins.Front()
+	return e.value, ok
+}
+
+// Max 返回窗口中的最大值，有多个最大值时返回最新加入的一个。
+// 如果窗口为空，返回零值和 false。
+func (w *SlidingWindow[T]) Max() (T, bool) {
+	w.expire()
+	e, ok := w.maxs.Front()
+	return e.value, ok
+}
+
+// Clear 清空窗口。
+func (w *SlidingWindow[T]) Clear() {
+	w.mins.Clear()
+	w.maxs.Clear()
+	if w.times != nil {
+		w.times.Clear()
+	}
+	w.oldest = w.next
+}
+
+// push 将元素加入两个单调队列，移除不可能再成为最值的候选。
+func (w *SlidingWindow[T]) push(x T) {
+	e := entry[T]{value: x, seq: w.next}
+	w.next++
+	for {
+		back, ok := w.mins.Back()
+		if !ok || w.cmp(back.value, x) < 0 {
+			break
+		}
+		w.mins.PopBack()
+	}
+	w.mins.PushBack(e)
+	for {
+		back, ok := w.maxs.Back()
+		if !ok || w.cmp(back.value, x) > 0 {
+			break
+		}
+		w.maxs.PopBack()
+	}
+	w.maxs.PushBack(e)
+}
+
+// expire 移出按时间划分的窗口中所有过期的元素。
+func (w *SlidingWindow[T]) expire() {
+	if w.times == nil {
+		return
+	}
+	cutoff := w.clock.Now().Add(-w.span)
+	for {
+		at, ok := w.times.Front()
+		if !ok || at.After(cutoff) {
+			return
+		}
+		w.Evict()
+	}
+}
@@ -0,0 +1,176 @@
+package slidingwindow
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeClock 是只能手动推进的时钟。
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestCountWindow(t *testing.T) {
+	w := NewOrderedSlidingWindow[int](3)
+	if _, ok := w.Min(); ok {
+		t.Fatal("Min on empty window should return false")
+	}
+
+	steps := []struct{ push, min, max int }{
+		{5, 5, 5},
+		{2, 2, 5},
+		{8, 2, 8},
+		{7, 2, 8},
+		{6, 6, 8},
+		{9, 6, 9},
+		{1, 1, 9},
+	}
+	for _, s := range steps {
+		w.Push(s.push)
+		minV, _ := w.Min()
+		maxV, _ := w.Max()
+		if minV != s.min || maxV != s.max {
+			t.Fatalf("after push %d expected min %d max %d, got %d %d", s.push, s.min, s.max, minV, maxV)
+		}
+	}
+	if w.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", w.Len())
+	}
+}
+
+func TestCountWindowMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const size = 7
+	w := NewOrderedSlidingWindow[int](size)
+	var all []int
+	for i := 0; i < 2000; i++ {
+		x := r.Intn(50)
+		w.Push(x)
+		all = append(all, x)
+		window := all[max(0, len(all)-size):]
+		minV, _ := w.Min()
+		maxV, _ := w.Max()
+		if minV != slices.Min(window) || maxV != slices.Max(window) {
+			t.Fatalf("step %d: expected min %d max %d, got %d %d", i, slices.Min(window), slices.Max(window), minV, maxV)
+		}
+	}
+}
+
+func TestEvict(t *testing.T) {
+	w := NewOrderedSlidingWindow[int](10)
+	for _, x := range []int{1, 9, 3, 7} {
+		w.Push(x)
+	}
+	w.Evict()
+	if minV, _ := w.Min(); minV != 3 {
+		t.Fatalf("expected min 3 after evicting 1, got %d", minV)
+	}
+	w.Evict()
+	if maxV, _ := w.Max(); maxV != 7 {
+		t.Fatalf("expected max 7 after evicting 9, got %d", maxV)
+	}
+	w.Evict()
+	w.Evict()
+	if w.Evict() || !w.IsEmpty() {
+		t.Fatal("Evict on empty window should return false")
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	w := NewOrderedSlidingWindow[int](2)
+	w.Push(4)
+	w.Push(4)
+	w.Push(4)
+	if minV, ok := w.Min(); !ok || minV != 4 {
+		t.Fatalf("expected min 4, got %d", minV)
+	}
+	w.Evict()
+	if maxV, ok := w.Max(); !ok || maxV != 4 {
+		t.Fatalf("expected max 4 with one element left, got %d", maxV)
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	w := NewTimeWindowWithClock(10*time.Second, func(a, b float64) int {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}, clock)
+
+	w.Push(3.5)
+	clock.Advance(4 * time.Second)
+	w.Push(1.0)
+	clock.Advance(4 * time.Second)
+	w.Push(2.0)
+
+	if minV, _ := w.Min(); minV != 1.0 {
+		t.Fatalf("expected min 1.0, got %v", minV)
+	}
+	if maxV, _ := w.Max(); maxV != 3.5 {
+		t.Fatalf("expected max 3.5, got %v", maxV)
+	}
+
+	clock.Advance(2 * time.Second)
+	if maxV, _ := w.Max(); maxV != 2.0 || w.Len() != 2 {
+		t.Fatalf("3.5 should expire at 10s, got max %v len %d", maxV, w.Len())
+	}
+	clock.Advance(4 * time.Second)
+	if minV, _ := w.Min(); minV != 2.0 || w.Len() != 1 {
+		t.Fatalf("1.0 should expire at 14s, got min %v len %d", minV, w.Len())
+	}
+	clock.Advance(10 * time.Second)
+	if !w.IsEmpty() {
+		t.Fatal("window should be empty after all elements expire")
+	}
+}
+
+func TestPushAt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start.Add(5 * time.Second)}
+	w := NewTimeWindowWithClock(5*time.Second, func(a, b int) int { return a - b }, clock)
+
+	w.PushAt(10, start)
+	if !w.IsEmpty() {
+		t.Fatal("element older than the window should expire immediately")
+	}
+	w.PushAt(1, start.Add(3*time.Second))
+	w.PushAt(2, start.Add(time.Second)) // 早于上一个元素，按上一个元素的时间处理
+	clock.Advance(3 * time.Second)
+	if !w.IsEmpty() {
+		t.Fatalf("both elements should expire together, got len %d", w.Len())
+	}
+}
+
+func TestClear(t *testing.T) {
+	w := NewOrderedSlidingWindow[string](3)
+	w.Push("b")
+	w.Push("a")
+	w.Clear()
+	if !w.IsEmpty() {
+		t.Fatal("window should be empty after Clear")
+	}
+	w.Push("c")
+	if minV, _ := w.Min(); minV != "c" {
+		t.Fatalf("expected min c, got %q", minV)
+	}
+}
+
+func TestInvalidSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for non-positive size")
+		}
+	}()
+	NewOrderedSlidingWindow[int](0)
+}
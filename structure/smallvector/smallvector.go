@@ -0,0 +1,281 @@
+// Package smallvector 提供了带内联存储的泛型动态数组，元素较少时不需要在堆上分配内存。
+package smallvector
+
+import (
+	"cmp"
+	"slices"
+	"unsafe"
+)
+
+// Inline 约束 SmallVector 的内联存储类型，即元素类型为 T、长度为 2 的幂的数组。
+type Inline[T any] interface {
+	~[1]T | ~[2]T | ~[4]T | ~[8]T | ~[16]T | ~[32]T | ~[64]T
+}
+
+// SmallVector 是一个带内联存储的动态数组，方法与 vector.Vector 一致。
+// 前 len(A) 个元素保存在结构体内的数组中，超过后所有元素迁移到堆上的切片。
+// 零值即为可用的空 SmallVector；作为局部变量且不超过内联容量时，操作不会产生堆分配。
+// 例如 SmallVector[int, [8]int] 可以在不分配内存的情况下保存最多 8 个 int。
+// SmallVector 包含内联数组，复制结构体会复制内联元素，应通过指针或 Clone 共享。
+type SmallVector[T any, A Inline[T]] struct {
+	inline A
+	n      int // 内联模式下的元素数量
+	heap   []T // 迁移到堆上之后保存所有元素，非 nil 表示处于堆模式
+}
+
+// NewSmallVector 创建一个 SmallVector，可选地传入初始元素。
+func NewSmallVector[T any, A Inline[T]](elements ...T) *SmallVector[T, A] {
+	v := &SmallVector[T, A]{}
+	v.PushBack(elements...)
+	return v
+}
+
+// Len 返回 SmallVector 中元素的数量。
+func (v *SmallVector[T, A]) Len() int {
+	if v.heap != nil {
+		return len(v.heap)
+	}
+	return v.n
+}
+
+// IsEmpty 检查 SmallVector 是否为空。
+func (v *SmallVector[T, A]) IsEmpty() bool {
+	return v.Len() == 0
+}
+
+// IsInline 检查元素是否仍然保存在内联存储中。
+func (v *SmallVector[T, A]) IsInline() bool {
+	return v.heap == nil
+}
+
+// PushBack 在 SmallVector 的末尾添加一个或多个元素。
+func (v *SmallVector[T, A]) PushBack(elements ...T) {
+	if v.heap == nil && v.n+len(elements) > len(v.inline) {
+		v.spill(v.n + len(elements))
+	}
+	if v.heap != nil {
+		v.heap = append(v.heap, elements...)
+		return
+	}
+	copy(v.buf()[v.n:], elements)
+	v.n += len(elements)
+}
+
+// PopBack 从 SmallVector 的末尾移除并返回最后一个元素。
+// 如果 SmallVector 为空，返回零值和 false。
+func (v *SmallVector[T, A]) PopBack() (T, bool) {
+	var zero T
+	if v.IsEmpty() {
+		return zero, false
+	}
+	if v.heap != nil {
+		elem := v.heap[len(v.heap)-1]
+		v.heap[len(v.heap)-1] = zero // 清零防止内存泄漏
+		v.heap = v.heap[:len(v.heap)-1]
+		return elem, true
+	}
+	v.n--
+	elem := v.inline[v.n]
+	v.inline[v.n] = zero
+	return elem, true
+}
+
+// At 返回指定索引处的元素，不检查边界。
+func (v *SmallVector[T, A]) At(index int) T {
+	return v.view()[index]
+}
+
+// Get 安全地返回指定索引处的元素。
+// 如果索引无效，返回零值和 false。
+func (v *SmallVector[T, A]) Get(index int) (T, bool) {
+	if index < 0 || index >= v.Len() {
+		var zero T
+		return zero, false
+	}
+	return v.view()[index], true
+}
+
+// Set 设置指定索引处的元素的值。
+// 如果索引无效返回 false。
+func (v *SmallVector[T, A]) Set(index int, value T) bool {
+	if index < 0 || index >= v.Len() {
+		return false
+	}
+	v.view()[index] = value
+	return true
+}
+
+// Insert 在指定索引处插入一个或多个元素。
+// 如果索引无效返回 false。
+func (v *SmallVector[T, A]) Insert(index int, elements ...T) bool {
+	if index < 0 || index > v.Len() {
+		return false
+	}
+	if v.heap == nil && v.n+len(elements) > len(v.inline) {
+		v.spill(v.n + len(elements))
+	}
+	if v.heap != nil {
+		v.heap = slices.Insert(v.heap, index, elements...)
+		return true
+	}
+	// 内联容量足够，slices.Insert 会原地移动元素
+	v.n = len(slices.Insert(v.view(), index, elements...))
+	return true
+}
+
+// Erase 移除指定范围内的元素 [begin, end)。
+// 如果范围无效返回 false。
+func (v *SmallVector[T, A]) Erase(begin, end int) bool {
+	if begin < 0 || end > v.Len() || begin >= end {
+		return false
+	}
+	if v.heap != nil {
+		v.heap = slices.Delete(v.heap, begin, end)
+		return true
+	}
+	v.n = len(slices.Delete(v.view(), begin, end))
+	return true
+}
+
+// Clear 移除 SmallVector 中的所有元素，已分配的堆内存会被保留。
+func (v *SmallVector[T, A]) Clear() {
+	if v.heap != nil {
+		clear(v.heap)
+		v.heap = v.heap[:0]
+		return
+	}
+	clear(v.view())
+	v.n = 0
+}
+
+// Front 返回 SmallVector 的第一个元素。
+// 如果 SmallVector 为空，返回零值和 false。
+func (v *SmallVector[T, A]) Front() (T, bool) {
+	return v.Get(0)
+}
+
+// Back 返回 SmallVector 的最后一个元素。
+// 如果 SmallVector 为空，返回零值和 false。
+func (v *SmallVector[T, A]) Back() (T, bool) {
+	return v.Get(v.Len() - 1)
+}
+
+// Capacity 返回 SmallVector 的当前容量，内联模式下为内联数组的长度。
+func (v *SmallVector[T, A]) Capacity() int {
+	if v.heap != nil {
+		return cap(v.heap)
+	}
+	return len(v.inline)
+}
+
+// Reserve 调整 SmallVector 的容量以至少容纳指定数量的元素。
+// 超过内联容量时元素会迁移到堆上。
+func (v *SmallVector[T, A]) Reserve(newCap int) {
+	if newCap <= v.Capacity() {
+		return
+	}
+	if v.heap == nil {
+		v.spill(newCap)
+		return
+	}
+	newData := make([]T, len(v.heap), newCap)
+	copy(newData, v.heap)
+	v.heap = newData
+}
+
+// Resize 调整 SmallVector 的大小。
+// 如果新大小大于当前大小，使用提供的值或零值填充新元素。
+func (v *SmallVector[T, A]) Resize(newSize int, value ...T) {
+	if newSize < 0 {
+		return
+	}
+	currSize := v.Len()
+	if newSize < currSize {
+		v.Erase(newSize, currSize)
+		return
+	}
+	if newSize == currSize {
+		return
+	}
+
+	v.Reserve(newSize)
+	var fill T
+	if len(value) > 0 {
+		fill = value[0]
+	}
+	if v.heap != nil {
+		v.heap = v.heap[:newSize]
+	} else {
+		v.n = newSize
+	}
+	data := v.view()
+	for i := currSize; i < newSize; i++ {
+		data[i] = fill
+	}
+}
+
+// Clone 创建并返回 SmallVector 的一个副本（深拷贝）。
+func (v *SmallVector[T, A]) Clone() *SmallVector[T, A] {
+	return NewSmallVector[T, A](v.view()...)
+}
+
+// ToSlice 将 SmallVector 转换为一个切片并返回。
+func (v *SmallVector[T, A]) ToSlice() []T {
+	return append([]T{}, v.view()...)
+}
+
+// Reverse 反转 SmallVector 中的元素顺序。
+func (v *SmallVector[T, A]) Reverse() {
+	slices.Reverse(v.view())
+}
+
+// Sort 使用自定义比较函数对 SmallVector 中的元素进行排序。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+func (v *SmallVector[T, A]) Sort(cmp func(a, b T) int) {
+	slices.SortFunc(v.view(), cmp)
+}
+
+// Contains 检查 SmallVector 是否包含指定的元素。
+func Contains[T comparable, A Inline[T]](v *SmallVector[T, A], element T) bool {
+	return slices.Contains(v.view(), element)
+}
+
+// IndexOf 返回指定元素在 SmallVector 中的索引，如果不存在则返回 -1。
+func IndexOf[T comparable, A Inline[T]](v *SmallVector[T, A], element T) int {
+	return slices.Index(v.view(), element)
+}
+
+// Sort 对 SmallVector 中可排序类型的元素进行升序排序。
+func Sort[T cmp.Ordered, A Inline[T]](v *SmallVector[T, A]) {
+	slices.Sort(v.view())
+}
+
+// Equal 检查两个 SmallVector 是否相等（元素和顺序均相同）。
+func Equal[T comparable, A Inline[T]](v1, v2 *SmallVector[T, A]) bool {
+	return slices.Equal(v1.view(), v2.view())
+}
+
+// buf 返回覆盖整个内联数组的切片。
+// 类型参数约束的数组不能直接切片，因此通过首元素的地址构造切片。
+// 返回的切片指向结构体内部，不能保存到结构体字段中，否则结构体会逃逸到堆上。
+func (v *SmallVector[T, A]) buf() []T {
+	return unsafe.Slice(&v.inline[0], len(v.inline))
+}
+
+// view 返回当前所有元素组成的切片。
+func (v *SmallVector[T, A]) view() []T {
+	if v.heap != nil {
+		return v.heap
+	}
+	return v.buf()[:v.n]
+}
+
+// spill 将内联元素迁移到容量至少为 minCap 的堆切片，并清空内联数组。
+func (v *SmallVector[T, A]) spill(minCap int) {
+	heap := make([]T, v.n, max(minCap, 2*len(v.inline)))
+	inline := v.buf()[:v.n]
+	copy(heap, inline)
+	clear(inline)
+	v.heap = heap
+	v.n = 0
+}
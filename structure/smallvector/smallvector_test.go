@@ -0,0 +1,197 @@
+package smallvector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Repeater11/go-template/structure/vector"
+)
+
+func TestZeroValue(t *testing.T) {
+	var v SmallVector[int, [4]int]
+	if !v.IsEmpty() || !v.IsInline() || v.Capacity() != 4 {
+		t.Fatal("zero value should be an empty inline vector")
+	}
+	if _, ok := v.PopBack(); ok {
+		t.Fatal("PopBack on empty vector should fail")
+	}
+	if _, ok := v.Front(); ok {
+		t.Fatal("Front on empty vector should fail")
+	}
+}
+
+func TestPushPopSpill(t *testing.T) {
+	var v SmallVector[int, [4]int]
+	for i := 0; i < 4; i++ {
+		v.PushBack(i)
+	}
+	if !v.IsInline() {
+		t.Fatal("vector should stay inline up to its inline capacity")
+	}
+	v.PushBack(4, 5)
+	if v.IsInline() || v.Len() != 6 {
+		t.Fatalf("vector should spill to the heap, len %d", v.Len())
+	}
+	if got := v.ToSlice(); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Fatalf("unexpected elements %v", got)
+	}
+	for i := 5; i >= 0; i-- {
+		if x, ok := v.PopBack(); !ok || x != i {
+			t.Fatalf("PopBack expected %d, got %d", i, x)
+		}
+	}
+	v.PushBack(9)
+	if back, _ := v.Back(); back != 9 || v.Len() != 1 {
+		t.Fatal("vector should be reusable after popping everything")
+	}
+}
+
+func TestGetSetAt(t *testing.T) {
+	v := NewSmallVector[string, [2]string]("a", "b")
+	if !v.Set(1, "B") || v.At(1) != "B" {
+		t.Fatal("Set should update the element")
+	}
+	if v.Set(2, "x") || v.Set(-1, "x") {
+		t.Fatal("Set with invalid index should fail")
+	}
+	if _, ok := v.Get(2); ok {
+		t.Fatal("Get with invalid index should fail")
+	}
+	v.PushBack("c")
+	if x, _ := v.Get(0); x != "a" || v.At(2) != "c" {
+		t.Fatal("elements should survive spilling")
+	}
+}
+
+// checkAgainst 比较 SmallVector 与作为参照的 vector.Vector。
+func checkAgainst(t *testing.T, sv *SmallVector[int, [8]int], ref *vector.Vector[int]) {
+	t.Helper()
+	if !slices.Equal(sv.ToSlice(), ref.ToSlice()) {
+		t.Fatalf("expected %v, got %v", ref.ToSlice(), sv.ToSlice())
+	}
+}
+
+func TestMatchesVector(t *testing.T) {
+	sv := NewSmallVector[int, [8]int]()
+	ref := vector.NewVector[int]()
+
+	ops := []func(){
+		func() { sv.PushBack(1, 2, 3); ref.PushBack(1, 2, 3) },
+		func() { sv.Insert(1, 10, 11); ref.Insert(1, 10, 11) },
+		func() { sv.Insert(0, 20); ref.Insert(0, 20) },
+		func() { sv.Erase(2, 4); ref.Erase(2, 4) },
+		func() { sv.Resize(7, 5); ref.Resize(7, 5) },
+		func() { sv.Insert(7, 30, 31, 32); ref.Insert(7, 30, 31, 32) },
+		func() { sv.Erase(0, 3); ref.Erase(0, 3) },
+		func() { sv.Resize(3); ref.Resize(3) },
+		func() { sv.Resize(12); ref.Resize(12) },
+		func() { sv.Reverse(); ref.Reverse() },
+		func() { Sort(sv); vector.Sort(ref) },
+		func() { sv.Sort(func(a, b int) int { return b - a }); ref.Sort(func(a, b int) int { return b - a }) },
+	}
+	for i, op := range ops {
+		op()
+		checkAgainst(t, sv, ref)
+		if sv.Len() != ref.Len() {
+			t.Fatalf("op %d: expected len %d, got %d", i, ref.Len(), sv.Len())
+		}
+	}
+
+	if sv.Insert(-1, 0) || sv.Insert(sv.Len()+1, 0) {
+		t.Fatal("Insert with invalid index should fail")
+	}
+	if sv.Erase(2, 2) || sv.Erase(0, sv.Len()+1) {
+		t.Fatal("Erase with invalid range should fail")
+	}
+}
+
+func TestInlineInsertErase(t *testing.T) {
+	v := NewSmallVector[int, [8]int](1, 2, 5)
+	v.Insert(2, 3, 4)
+	v.Erase(0, 1)
+	if !v.IsInline() {
+		t.Fatal("operations within inline capacity should stay inline")
+	}
+	if got := v.ToSlice(); !slices.Equal(got, []int{2, 3, 4, 5}) {
+		t.Fatalf("unexpected elements %v", got)
+	}
+}
+
+func TestReserveAndClear(t *testing.T) {
+	var v SmallVector[int, [4]int]
+	v.PushBack(1, 2)
+	v.Reserve(3)
+	if !v.IsInline() {
+		t.Fatal("Reserve within inline capacity should not spill")
+	}
+	v.Reserve(100)
+	if v.IsInline() || v.Capacity() < 100 {
+		t.Fatalf("Reserve should move to the heap, capacity %d", v.Capacity())
+	}
+	if got := v.ToSlice(); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("Reserve should keep elements, got %v", got)
+	}
+	v.Clear()
+	if !v.IsEmpty() || v.Capacity() < 100 {
+		t.Fatal("Clear should keep the heap capacity")
+	}
+}
+
+func TestCloneEqualContains(t *testing.T) {
+	v := NewSmallVector[int, [2]int](3, 1, 2)
+	c := v.Clone()
+	if !Equal(v, c) {
+		t.Fatal("clone should be equal")
+	}
+	c.Set(0, 9)
+	if Equal(v, c) || v.At(0) != 3 {
+		t.Fatal("clone should not share storage")
+	}
+	if !Contains(v, 2) || Contains(v, 9) || IndexOf(v, 1) != 1 || IndexOf(v, 7) != -1 {
+		t.Fatal("unexpected Contains/IndexOf results")
+	}
+}
+
+func TestAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		var v SmallVector[int, [8]int]
+		for i := 0; i < 7; i++ {
+			v.PushBack(i)
+		}
+		v.Insert(0, -1)
+		v.Erase(0, 1)
+		v.Sort(func(a, b int) int { return b - a })
+		for v.Len() > 0 {
+			v.PopBack()
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations within inline capacity, got %v", allocs)
+	}
+}
+
+func BenchmarkSmallVector(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		var v SmallVector[int, [8]int]
+		for i := 0; i < 8; i++ {
+			v.PushBack(i)
+		}
+		for v.Len() > 0 {
+			v.PopBack()
+		}
+	}
+}
+
+func BenchmarkVector(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		v := vector.NewVector[int]()
+		for i := 0; i < 8; i++ {
+			v.PushBack(i)
+		}
+		for v.Len() > 0 {
+			v.PopBack()
+		}
+	}
+}
@@ -0,0 +1,360 @@
+// Package spillqueue 提供了内存超限时将元素溢出到临时文件的泛型先进先出队列实现。
+package spillqueue
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/Repeater11/go-template/structure/codec"
+	"github.com/Repeater11/go-template/structure/deque"
+)
+
+// defaultMaxItems 是未设置内存上限时默认保留在内存中的元素数量。
+const defaultMaxItems = 1024
+
+// ErrEmpty 表示队列为空。
+var ErrEmpty = errors.New("spillqueue: queue is empty")
+
+// Options 配置 SpillQueue 的内存上限和临时文件位置。
+type Options[T any] struct {
+	// MaxItems 是内存中最多保留的元素数量，不为正数时不按数量限制。
+	MaxItems int
+	// MaxBytes 是内存中元素的总大小上限，由 Size 计算每个元素的大小，不为正数时不按大小限制。
+	MaxBytes int64
+	// Size 返回元素占用的字节数，设置 MaxBytes 时必须提供。
+	Size func(T) int64
+	// Dir 是存放临时文件的目录，为空时使用 os.TempDir()。
+	Dir string
+}
+
+// segment 是一个溢出到磁盘的连续元素片段。
+type segment struct {
+	path  string
+	count int
+}
+
+// SpillQueue 是一个在内存不足时自动溢出到磁盘的先进先出队列。
+// 队列由三部分组成：内存中的头部 Deque、按顺序排列的临时文件片段以及内存中的尾部 Deque。
+// 内存超过上限时，尾部（或没有片段时头部的后半部分）会被编码写入新的临时文件；
+// 头部耗尽时按顺序从文件中重新加载片段，因此元素始终保持先进先出的顺序。
+// 临时文件只会在 Clear 或 Close 时删除，不再使用的 SpillQueue 必须调用 Close，否则溢出的临时文件会残留在磁盘上。
+// SpillQueue 不是并发安全的。
+type SpillQueue[T any] struct {
+	codec codec.Codec[T]
+	opts  Options[T]
+
+	head     *deque.Deque[T]
+	tail     *deque.Deque[T]
+	segments *deque.Deque[segment] // 位于 head 与 tail 之间的片段
+	spilled  int                   // 片段中元素的总数
+	memBytes int64                 // head 与 tail 中元素的总大小
+	dir      string                // 临时目录，首次溢出时创建
+	retryIn  int                   // 溢出失败后，再次尝试溢出前需要跳过的次数
+	err      error
+}
+
+// NewSpillQueue 创建一个使用指定编解码器的空 SpillQueue。
+// 如果 opts 既没有设置 MaxItems 也没有设置 MaxBytes，内存中最多保留 1024 个元素。
+// 如果设置了 MaxBytes 而没有提供 Size，会引发 panic。
+func NewSpillQueue[T any](c codec.Codec[T], opts Options[T]) *SpillQueue[T] {
+	if opts.MaxBytes > 0 && opts.Size == nil {
+		panic("spillqueue: MaxBytes requires a Size function")
+	}
+	if opts.MaxItems <= 0 && opts.MaxBytes <= 0 {
+		opts.MaxItems = defaultMaxItems
+	}
+	return &SpillQueue[T]{
+		codec:    c,
+		opts:     opts,
+		head:     deque.NewDeque[T](),
+		tail:     deque.NewDeque[T](),
+		segments: deque.NewDeque[segment](),
+	}
+}
+
+// Len 返回队列中元素的数量，包括溢出到磁盘的元素。
+func (q *SpillQueue[T]) Len() int {
+	return q.head.Len() + q.spilled + q.tail.Len()
+}
+
+// IsEmpty 检查队列是否为空。
+func (q *SpillQueue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// InMemory 返回当前保存在内存中的元素数量。
+func (q *SpillQueue[T]) InMemory() int {
+	return q.head.Len() + q.tail.Len()
+}
+
+// Err 返回最近一次读写临时文件时发生的错误，没有错误时返回 nil。
+// 之后成功溢出或加载片段会清除该错误。
+// 写入失败时元素会继续保留在内存中，并且在内存中的元素数量翻倍之前不再尝试溢出；
+// 读取失败时片段会保留，之后的 Pop 会重试。
+func (q *SpillQueue[T]) Err() error {
+	return q.err
+}
+
+// Push 在队列尾部添加一个元素，必要时将部分元素溢出到磁盘。
+func (q *SpillQueue[T]) Push(elem T) {
+	if q.segments.IsEmpty() && q.tail.IsEmpty() {
+		q.head.PushBack(elem)
+	} else {
+		q.tail.PushBack(elem)
+	}
+	q.memBytes += q.size(elem)
+	q.shrink()
+}
+
+// Front 返回队列头部的元素但不移除它。
+// 如果队列为空，返回零值和 false。加载片段失败时同样返回 false，
+// 但 Len 仍大于 0，需要区分这两种情况时应使用 FrontErr。
+func (q *SpillQueue[T]) Front() (T, bool) {
+	elem, err := q.FrontErr()
+	return elem, err == nil
+}
+
+// FrontErr 返回队列头部的元素但不移除它。
+// 如果队列为空，返回零值和 ErrEmpty；加载片段失败时返回对应的错误。
+func (q *SpillQueue[T]) FrontErr() (T, error) {
+	var zero T
+	if err := q.fill(); err != nil {
+		return zero, err
+	}
+	elem, ok := q.head.Front()
+	if !ok {
+		return zero, ErrEmpty
+	}
+	return elem, nil
+}
+
+// Pop 移除并返回队列头部的元素。
+// 如果队列为空，返回零值和 false。加载片段失败时同样返回 false，
+// 但 Len 仍大于 0，需要区分这两种情况时应使用 PopErr。
+func (q *SpillQueue[T]) Pop() (T, bool) {
+	elem, err := q.PopErr()
+	return elem, err == nil
+}
+
+// PopErr 移除并返回队列头部的元素。
+// 如果队列为空，返回零值和 ErrEmpty；加载片段失败时返回对应的错误，此时不会移除任何元素。
+func (q *SpillQueue[T]) PopErr() (T, error) {
+	var zero T
+	if err := q.fill(); err != nil {
+		return zero, err
+	}
+	elem, ok := q.head.PopFront()
+	if !ok {
+		return zero, ErrEmpty
+	}
+	q.memBytes -= q.size(elem)
+	return elem, nil
+}
+
+// Clear 清空队列并删除所有临时文件。
+func (q *SpillQueue[T]) Clear() {
+	q.head.Clear()
+	q.tail.Clear()
+	for !q.segments.IsEmpty() {
+		s, _ := q.segments.PopFront()
+		q.remove(s.path)
+	}
+	q.spilled = 0
+	q.memBytes = 0
+	q.retryIn = 0
+}
+
+// Close 清空队列并删除临时目录，返回删除过程中发生的错误。
+// Close 之后队列仍然可以继续使用。
+func (q *SpillQueue[T]) Close() error {
+	q.Clear()
+	if q.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(q.dir)
+	q.dir = ""
+	return err
+}
+
+// size 返回元素按字节计算的大小，未按字节限制时返回 0。
+func (q *SpillQueue[T]) size(elem T) int64 {
+	if q.opts.MaxBytes <= 0 {
+		return 0
+	}
+	return q.opts.Size(elem)
+}
+
+// overLimit 检查内存中的元素是否超过上限。
+func (q *SpillQueue[T]) overLimit() bool {
+	if q.opts.MaxItems > 0 && q.InMemory() > q.opts.MaxItems {
+		return true
+	}
+	return q.opts.MaxBytes > 0 && q.memBytes > q.opts.MaxBytes
+}
+
+// shrink 在内存超过上限时将元素溢出到磁盘。
+// 优先溢出整个尾部；没有尾部时将头部的后半部分作为新的第一个片段溢出。
+// 溢出失败后会跳过之后的若干次调用，避免每次 Push 都重新编码整个尾部。
+func (q *SpillQueue[T]) shrink() {
+	if q.retryIn > 0 {
+		q.retryIn--
+		return
+	}
+	for q.overLimit() {
+		if !q.tail.IsEmpty() {
+			elems := q.tail.ToSlice()
+			if !q.spill(elems, false) {
+				return
+			}
+			q.tail.Clear()
+			continue
+		}
+		if q.head.Len() < 2 {
+			return
+		}
+		half := q.head.Len() / 2
+		elems := q.head.ToSlice()[half:]
+		if !q.spill(elems, true) {
+			return
+		}
+		q.head.Erase(half, q.head.Len())
+	}
+}
+
+// spill 将 elems 写入一个新的临时文件，front 为 true 时片段位于所有已有片段之前。
+// 写入成功后会从 memBytes 中扣除这些元素的大小。
+func (q *SpillQueue[T]) spill(elems []T, front bool) bool {
+	path, err := q.write(elems)
+	if err != nil {
+		q.err = err
+		// 重试的代价与内存中的元素数量成正比，等待同样多次调用后再重试，均摊到每次 Push 为 O(1)
+		q.retryIn = q.InMemory()
+		return false
+	}
+	q.err = nil
+	s := segment{path: path, count: len(elems)}
+	if front {
+		q.segments.PushFront(s)
+	} else {
+		q.segments.PushBack(s)
+	}
+	q.spilled += len(elems)
+	for _, elem := range elems {
+		q.memBytes -= q.size(elem)
+	}
+	return true
+}
+
+// write 将 elems 以长度前缀的格式编码写入一个新的临时文件，返回文件路径。
+func (q *SpillQueue[T]) write(elems []T) (path string, err error) {
+	if q.dir == "" {
+		if q.dir, err = os.MkdirTemp(q.opts.Dir, "spillqueue-"); err != nil {
+			q.dir = ""
+			return "", err
+		}
+	}
+	f, err := os.CreateTemp(q.dir, "*.seg")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	var header [4]byte
+	for _, elem := range elems {
+		data, err := q.codec.Encode(elem)
+		if err != nil {
+			return "", err
+		}
+		binary.LittleEndian.PutUint32(header[:], uint32(len(data)))
+		if _, err := w.Write(header[:]); err != nil {
+			return "", err
+		}
+		if _, err := w.Write(data); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// fill 在头部为空时加载下一个片段，没有片段时将尾部移到头部。
+func (q *SpillQueue[T]) fill() error {
+	if !q.head.IsEmpty() {
+		return nil
+	}
+	if q.segments.IsEmpty() {
+		q.head, q.tail = q.tail, q.head
+		return nil
+	}
+
+	s, _ := q.segments.Front()
+	elems, err := q.read(s)
+	if err != nil {
+		q.err = err
+		return err
+	}
+	q.err = nil
+	q.segments.PopFront()
+	q.spilled -= s.count
+	q.remove(s.path)
+	for _, elem := range elems {
+		q.head.PushBack(elem)
+		q.memBytes += q.size(elem)
+	}
+	q.shrink()
+	return nil
+}
+
+// read 读取并解码一个片段中的所有元素。
+func (q *SpillQueue[T]) read(s segment) ([]T, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	elems := make([]T, 0, s.count)
+	var header [4]byte
+	var data []byte
+	for range s.count {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, err
+		}
+		n := binary.LittleEndian.Uint32(header[:])
+		if cap(data) < int(n) {
+			data = make([]byte, n)
+		}
+		data = data[:n]
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		elem, err := q.codec.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+	}
+	return elems, nil
+}
+
+// remove 删除一个临时文件并记录失败。
+func (q *SpillQueue[T]) remove(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		q.err = err
+	}
+}
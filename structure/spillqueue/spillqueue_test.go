@@ -0,0 +1,265 @@
+package spillqueue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Repeater11/go-template/structure/codec"
+)
+
+func spillFiles(t *testing.T, q *SpillQueue[int]) int {
+	t.Helper()
+	if q.dir == "" {
+		return 0
+	}
+	files, err := filepath.Glob(filepath.Join(q.dir, "*.seg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestFIFOWithSpill(t *testing.T) {
+	q := NewSpillQueue[int](codec.JSON[int]{}, Options[int]{MaxItems: 10, Dir: t.TempDir()})
+	defer q.Close()
+
+	const n = 1000
+	for i := 0; i < n; i++ {
+		q.Push(i)
+		if q.InMemory() > 10 {
+			t.Fatalf("in-memory count %d exceeds limit", q.InMemory())
+		}
+	}
+	if q.Len() != n {
+		t.Fatalf("expected len %d, got %d", n, q.Len())
+	}
+	if spillFiles(t, q) == 0 {
+		t.Fatal("expected elements to be spilled to disk")
+	}
+
+	for i := 0; i < n; i++ {
+		if front, ok := q.Front(); !ok || front != i {
+			t.Fatalf("expected front %d, got %d", i, front)
+		}
+		if got, ok := q.Pop(); !ok || got != i {
+			t.Fatalf("expected %d, got %d", i, got)
+		}
+	}
+	if _, ok := q.Pop(); ok || !q.IsEmpty() {
+		t.Fatal("queue should be empty")
+	}
+	if spillFiles(t, q) != 0 {
+		t.Fatal("consumed segments should be deleted")
+	}
+	if err := q.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInterleaved(t *testing.T) {
+	q := NewSpillQueue[int](codec.JSON[int]{}, Options[int]{MaxItems: 4, Dir: t.TempDir()})
+	defer q.Close()
+
+	next, expect := 0, 0
+	for round := 0; round < 50; round++ {
+		for i := 0; i < round%7+1; i++ {
+			q.Push(next)
+			next++
+		}
+		for i := 0; i < round%5; i++ {
+			got, ok := q.Pop()
+			if !ok {
+				break
+			}
+			if got != expect {
+				t.Fatalf("expected %d, got %d", expect, got)
+			}
+			expect++
+		}
+		if q.Len() != next-expect {
+			t.Fatalf("expected len %d, got %d", next-expect, q.Len())
+		}
+	}
+	for !q.IsEmpty() {
+		if got, _ := q.Pop(); got != expect {
+			t.Fatalf("expected %d, got %d", expect, got)
+		}
+		expect++
+	}
+	if expect != next {
+		t.Fatalf("lost elements: popped %d of %d", expect, next)
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	size := func(s string) int64 { return int64(len(s)) }
+	q := NewSpillQueue[string](codec.String{}, Options[string]{MaxBytes: 20, Size: size, Dir: t.TempDir()})
+	defer q.Close()
+
+	words := []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta"}
+	for _, w := range words {
+		q.Push(w)
+		if q.memBytes > 20 {
+			t.Fatalf("in-memory bytes %d exceed limit", q.memBytes)
+		}
+	}
+	for _, want := range words {
+		if got, ok := q.Pop(); !ok || got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+	if q.memBytes != 0 {
+		t.Fatalf("expected 0 bytes in memory, got %d", q.memBytes)
+	}
+}
+
+func TestMaxBytesRequiresSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic without Size")
+		}
+	}()
+	NewSpillQueue[int](codec.JSON[int]{}, Options[int]{MaxBytes: 10})
+}
+
+func TestClearAndClose(t *testing.T) {
+	q := NewSpillQueue[int](codec.JSON[int]{}, Options[int]{MaxItems: 2, Dir: t.TempDir()})
+	for i := 0; i < 20; i++ {
+		q.Push(i)
+	}
+	dir := q.dir
+	q.Clear()
+	if !q.IsEmpty() || spillFiles(t, q) != 0 {
+		t.Fatal("Clear should drop all elements and files")
+	}
+
+	q.Push(1)
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatal("Close should remove the temporary directory")
+	}
+}
+
+func TestReadErrorKeepsSegment(t *testing.T) {
+	q := NewSpillQueue[int](codec.JSON[int]{}, Options[int]{MaxItems: 2, Dir: t.TempDir()})
+	defer q.Close()
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	q.Pop()
+
+	s, _ := q.segments.Front()
+	data, _ := os.ReadFile(s.path)
+	os.WriteFile(s.path, data[:len(data)-1], 0o644)
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Pop should fail when the segment is truncated")
+	}
+	if q.Err() == nil {
+		t.Fatal("Err should report the read failure")
+	}
+	if q.Len() != 9 {
+		t.Fatalf("failed segment should be kept, got len %d", q.Len())
+	}
+	if _, err := q.PopErr(); err == nil || errors.Is(err, ErrEmpty) {
+		t.Fatalf("PopErr should report the read failure, got %v", err)
+	}
+	if _, err := q.FrontErr(); err == nil || errors.Is(err, ErrEmpty) {
+		t.Fatalf("FrontErr should report the read failure, got %v", err)
+	}
+}
+
+func TestPopErrEmpty(t *testing.T) {
+	q := NewSpillQueue[int](codec.JSON[int]{}, Options[int]{})
+	defer q.Close()
+	if _, err := q.PopErr(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("PopErr on empty queue should return ErrEmpty, got %v", err)
+	}
+	q.Push(1)
+	if got, err := q.FrontErr(); err != nil || got != 1 {
+		t.Fatalf("expected front 1, got %d (%v)", got, err)
+	}
+	if got, err := q.PopErr(); err != nil || got != 1 {
+		t.Fatalf("expected 1, got %d (%v)", got, err)
+	}
+}
+
+// failingCodec 无法编码最近一次入队的元素，并记录 Encode 被调用的次数。
+// 由于溢出时总是包含最新的元素，每次溢出都会在编码了大部分元素之后失败。
+type failingCodec struct {
+	codec.JSON[int]
+	latest  *int
+	encodes *int
+}
+
+func (c failingCodec) Encode(v int) ([]byte, error) {
+	*c.encodes++
+	if v == *c.latest {
+		return nil, errors.New("encode failed")
+	}
+	return c.JSON.Encode(v)
+}
+
+func TestSpillErrorBackoff(t *testing.T) {
+	var latest, encodes int
+	c := failingCodec{latest: &latest, encodes: &encodes}
+	q := NewSpillQueue[int](c, Options[int]{MaxItems: 4, Dir: t.TempDir()})
+	defer q.Close()
+	const n = 2000
+	for i := 0; i < n; i++ {
+		latest = i
+		q.Push(i)
+	}
+	if q.Err() == nil {
+		t.Fatal("Err should report the spill failure")
+	}
+	if q.InMemory() != n {
+		t.Fatalf("elements should stay in memory after a failed spill, got %d", q.InMemory())
+	}
+	// 每次失败后等待的次数与内存中的元素数量成正比，编码总次数应与 n 同阶
+	if encodes > 4*n {
+		t.Fatalf("failed spills should back off, got %d encodes for %d pushes", encodes, n)
+	}
+	for i := 0; i < n; i++ {
+		if got, ok := q.Pop(); !ok || got != i {
+			t.Fatalf("expected %d, got %d", i, got)
+		}
+	}
+}
+
+func TestErrClearedAfterRecovery(t *testing.T) {
+	var latest, encodes int
+	c := failingCodec{latest: &latest, encodes: &encodes}
+	q := NewSpillQueue[int](c, Options[int]{MaxItems: 4, Dir: t.TempDir()})
+	defer q.Close()
+	for i := 0; i < 10; i++ {
+		latest = i
+		q.Push(i)
+	}
+	if q.Err() == nil {
+		t.Fatal("Err should report the spill failure")
+	}
+
+	// 写入恢复正常后，等待退避结束的下一次溢出应当成功并清除错误
+	latest = -1
+	for i := 10; i < 40; i++ {
+		q.Push(i)
+	}
+	if err := q.Err(); err != nil {
+		t.Fatalf("Err should be cleared after a successful spill, got %v", err)
+	}
+	if spillFiles(t, q) == 0 {
+		t.Fatal("elements should be spilled after recovery")
+	}
+	for i := 0; i < 40; i++ {
+		if got, ok := q.Pop(); !ok || got != i {
+			t.Fatalf("expected %d, got %d", i, got)
+		}
+	}
+	if err := q.Err(); err != nil {
+		t.Fatalf("Err should stay nil after successful reloads, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+// Package spsc 提供了单生产者单消费者的无锁有界队列实现。
+package spsc
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// cacheLineSize 是用于填充的缓存行大小，避免生产者和消费者的字段伪共享。
+const cacheLineSize = 64
+
+// spinLimit 是阻塞操作在让出处理器之前自旋的次数。
+const spinLimit = 64
+
+// Queue 是一个单生产者单消费者的无锁有界队列。
+// 同一时刻最多只能有一个 goroutine 调用写入方法（TryPush、PushN、PushWait），
+// 并且最多只能有一个 goroutine 调用读取方法（TryPop、PopN、PopWait）。
+type Queue[T any] struct {
+	_ [cacheLineSize]byte
+
+	// 由消费者写入
+	head       atomic.Uint64 // 下一个待读取的位置
+	cachedTail uint64        // 消费者缓存的 tail，减少对生产者缓存行的访问
+	_          [cacheLineSize - 16]byte
+
+	// 由生产者写入
+	tail       atomic.Uint64 // 下一个待写入的位置
+	cachedHead uint64        // 生产者缓存的 head，减少对消费者缓存行的访问
+	_          [cacheLineSize - 16]byte
+
+	// 初始化后只读
+	buf  []T
+	mask uint64
+}
+
+// NewQueue 创建一个至少能容纳 capacity 个元素的队列。
+// 实际容量会向上取整为 2 的幂。如果容量不是正数会引发 panic。
+func NewQueue[T any](capacity int) *Queue[T] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("spsc: non-positive capacity %d", capacity))
+	}
+	size := 1
+	for size < capacity {
+		size <<= 1
+	}
+	return &Queue[T]{
+		buf:  make([]T, size),
+		mask: uint64(size - 1),
+	}
+}
+
+// Cap 返回队列的容量。
+func (q *Queue[T]) Cap() int {
+	return len(q.buf)
+}
+
+// Len 返回队列中元素的数量。
+// 在并发读写期间返回的只是一个近似值。
+func (q *Queue[T]) Len() int {
+	head := q.head.Load()
+	tail := q.tail.Load()
+	if tail < head {
+		return 0
+	}
+	return int(tail - head)
+}
+
+// IsEmpty 检查队列是否为空。
+// 在并发读写期间返回的只是一个近似值。
+func (q *Queue[T]) IsEmpty() bool {
+	return q.Len() == 0
+}
+
+// TryPush 尝试在队列尾部添加一个元素，只能由生产者调用。
+// 如果队列已满返回 false。
+func (q *Queue[T]) TryPush(elem T) bool {
+	tail := q.tail.Load()
+	if tail-q.cachedHead == uint64(len(q.buf)) {
+		q.cachedHead = q.head.Load()
+		if tail-q.cachedHead == uint64(len(q.buf)) {
+			return false
+		}
+	}
+	q.buf[tail&q.mask] = elem
+	q.tail.Store(tail + 1)
+	return true
+}
+
+// TryPop 尝试从队列头部移除并返回一个元素，只能由消费者调用。
+// 如果队列为空，返回零值和 false。
+func (q *Queue[T]) TryPop() (T, bool) {
+	var zero T
+	head := q.head.Load()
+	if head == q.cachedTail {
+		q.cachedTail = q.tail.Load()
+		if head == q.cachedTail {
+			return zero, false
+		}
+	}
+	elem := q.buf[head&q.mask]
+	q.buf[head&q.mask] = zero // 清零防止内存泄漏
+	q.head.Store(head + 1)
+	return elem, true
+}
+
+// PushN 尽可能多地将 elems 中的元素按顺序添加到队列尾部，只能由生产者调用。
+// 返回实际添加的元素数量。
+func (q *Queue[T]) PushN(elems []T) int {
+	tail := q.tail.Load()
+	free := uint64(len(q.buf)) - (tail - q.cachedHead)
+	if free < uint64(len(elems)) {
+		q.cachedHead = q.head.Load()
+		free = uint64(len(q.buf)) - (tail - q.cachedHead)
+	}
+	n := min(uint64(len(elems)), free)
+	for i := uint64(0); i < n; i++ {
+		q.buf[(tail+i)&q.mask] = elems[i]
+	}
+	if n > 0 {
+		q.tail.Store(tail + n)
+	}
+	return int(n)
+}
+
+// PopN 从队列头部最多移除 len(dst) 个元素并按顺序写入 dst，只能由消费者调用。
+// 返回实际移除的元素数量。
+func (q *Queue[T]) PopN(dst []T) int {
+	var zero T
+	head := q.head.Load()
+	avail := q.cachedTail - head
+	if avail < uint64(len(dst)) {
+		q.cachedTail = q.tail.Load()
+		avail = q.cachedTail - head
+	}
+	n := min(uint64(len(dst)), avail)
+	for i := uint64(0); i < n; i++ {
+		idx := (head + i) & q.mask
+		dst[i] = q.buf[idx]
+		q.buf[idx] = zero // 清零防止内存泄漏
+	}
+	if n > 0 {
+		q.head.Store(head + n)
+	}
+	return int(n)
+}
+
+// PushWait 在队列尾部添加一个元素，队列已满时等待直到有空位，只能由生产者调用。
+// 如果 ctx 在添加成功前被取消，返回 ctx.Err()。
+func (q *Queue[T]) PushWait(ctx context.Context, elem T) error {
+	for spins := 0; ; spins++ {
+		if q.TryPush(elem) {
+			return nil
+		}
+		if err := backoff(ctx, spins); err != nil {
+			return err
+		}
+	}
+}
+
+// PopWait 从队列头部移除并返回一个元素，队列为空时等待直到有元素，只能由消费者调用。
+// 如果 ctx 在取到元素前被取消，返回零值和 ctx.Err()。
+func (q *Queue[T]) PopWait(ctx context.Context) (T, error) {
+	for spins := 0; ; spins++ {
+		if elem, ok := q.TryPop(); ok {
+			return elem, nil
+		}
+		if err := backoff(ctx, spins); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+// backoff 根据已等待的次数自旋、让出处理器或短暂休眠，并检查 ctx 是否已取消。
+func backoff(ctx context.Context, spins int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	switch {
+	case spins < spinLimit:
+		// 忙等待，适合另一端即将完成操作的情况
+	case spins < 2*spinLimit:
+		runtime.Gosched()
+	default:
+		time.Sleep(50 * time.Microsecond)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package spsc
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Repeater11/go-template/structure/queue"
+)
+
+func TestNewQueue(t *testing.T) {
+	q := NewQueue[int](5)
+	if q.Cap() != 8 {
+		t.Fatalf("capacity should round up to 8, got %d", q.Cap())
+	}
+	if q.Len() != 0 || !q.IsEmpty() {
+		t.Fatalf("expected empty queue, got len %d", q.Len())
+	}
+	if _, ok := q.TryPop(); ok {
+		t.Fatal("TryPop on empty queue should fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("zero capacity should panic")
+		}
+	}()
+	NewQueue[int](0)
+}
+
+func TestTryPushPop(t *testing.T) {
+	q := NewQueue[int](4)
+	for i := 0; i < 4; i++ {
+		if !q.TryPush(i) {
+			t.Fatalf("TryPush(%d) should succeed", i)
+		}
+	}
+	if q.TryPush(4) {
+		t.Fatal("TryPush on full queue should fail")
+	}
+	for i := 0; i < 4; i++ {
+		if v, ok := q.TryPop(); !ok || v != i {
+			t.Fatalf("TryPop expected (%d,true), got (%d,%v)", i, v, ok)
+		}
+	}
+	if !q.TryPush(4) {
+		t.Fatal("TryPush should succeed after draining")
+	}
+}
+
+func TestPushNPopN(t *testing.T) {
+	q := NewQueue[int](8)
+	if n := q.PushN([]int{1, 2, 3, 4, 5}); n != 5 {
+		t.Fatalf("PushN expected 5, got %d", n)
+	}
+	if n := q.PushN([]int{6, 7, 8, 9, 10}); n != 3 {
+		t.Fatalf("PushN into partially full queue expected 3, got %d", n)
+	}
+
+	dst := make([]int, 6)
+	if n := q.PopN(dst); n != 6 || !slices.Equal(dst, []int{1, 2, 3, 4, 5, 6}) {
+		t.Fatalf("PopN unexpected result %d %v", n, dst)
+	}
+	if n := q.PopN(dst); n != 2 || !slices.Equal(dst[:n], []int{7, 8}) {
+		t.Fatalf("PopN unexpected result %d %v", n, dst[:n])
+	}
+	if n := q.PopN(dst); n != 0 {
+		t.Fatalf("PopN on empty queue expected 0, got %d", n)
+	}
+}
+
+func TestWaitCancellation(t *testing.T) {
+	q := NewQueue[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := q.PopWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PopWait on empty queue expected deadline error, got %v", err)
+	}
+	q.TryPush(1)
+	if err := q.PushWait(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PushWait on full queue expected deadline error, got %v", err)
+	}
+}
+
+// TestConcurrentOrder 在竞态检测下验证单生产者单消费者时的 FIFO 顺序。
+func TestConcurrentOrder(t *testing.T) {
+	const n = 100000
+	q := NewQueue[int](64)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		batch := make([]int, 0, 7)
+		for i := 0; i < n; {
+			// 交替使用单个和批量写入
+			if i%3 == 0 {
+				if err := q.PushWait(ctx, i); err != nil {
+					t.Error(err)
+					return
+				}
+				i++
+				continue
+			}
+			batch = batch[:0]
+			for j := i; j < n && len(batch) < cap(batch); j++ {
+				batch = append(batch, j)
+			}
+			pushed := q.PushN(batch)
+			if pushed == 0 {
+				runtime.Gosched()
+			}
+			i += pushed
+		}
+	}()
+
+	buf := make([]int, 5)
+	for next := 0; next < n; {
+		if next%2 == 0 {
+			v, err := q.PopWait(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != next {
+				t.Fatalf("expected %d, got %d", next, v)
+			}
+			next++
+			continue
+		}
+		k := q.PopN(buf)
+		if k == 0 {
+			runtime.Gosched()
+		}
+		for _, v := range buf[:k] {
+			if v != next {
+				t.Fatalf("expected %d, got %d", next, v)
+			}
+			next++
+		}
+	}
+	wg.Wait()
+	if !q.IsEmpty() {
+		t.Fatalf("queue should be empty, len %d", q.Len())
+	}
+}
+
+func BenchmarkSPSC(b *testing.B) {
+	q := NewQueue[int](1024)
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < b.N; i++ {
+			q.PopWait(ctx)
+		}
+		close(done)
+	}()
+	for i := 0; i < b.N; i++ {
+		q.PushWait(ctx, i)
+	}
+	<-done
+}
+
+func BenchmarkMutexQueue(b *testing.B) {
+	var mu sync.Mutex
+	q := queue.NewQueue[int]()
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < b.N; {
+			mu.Lock()
+			_, ok := q.Pop()
+			mu.Unlock()
+			if ok {
+				i++
+			}
+		}
+		close(done)
+	}()
+	for i := 0; i < b.N; i++ {
+		mu.Lock()
+		q.Push(i)
+		mu.Unlock()
+	}
+	<-done
+}
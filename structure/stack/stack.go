@@ -55,6 +55,16 @@ func (s *Stack[T]) Pop() (T, bool) {
 	return s.deque.PopBack()
 }
 
+// PopBottom 弹出并返回栈底元素，若栈为空返回零值和 false。
+// 可用于限制栈的深度时丢弃最早压入的元素。
+func (s *Stack[T]) PopBottom() (T, bool) {
+	var zero T
+	if s == nil || s.deque == nil {
+		return zero, false
+	}
+	return s.deque.PopFront()
+}
+
 // Clear 清空栈中的所有元素。
 func (s *Stack[T]) Clear() {
 	if s == nil || s.deque == nil {
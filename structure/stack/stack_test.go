@@ -47,6 +47,28 @@ func TestTop(t *testing.T) {
 	}
 }
 
+func TestPopBottom(t *testing.T) {
+	s := NewStack[int]()
+	for i := 0; i < 3; i++ {
+		s.Push(i)
+	}
+	if val, ok := s.PopBottom(); !ok || val != 0 {
+		t.Fatalf("PopBottom expected (0,true), got (%v,%v)", val, ok)
+	}
+	if top, _ := s.Top(); top != 2 || s.Len() != 2 {
+		t.Fatalf("PopBottom should not affect top, got top %d len %d", top, s.Len())
+	}
+	s.PopBottom()
+	s.PopBottom()
+	if _, ok := s.PopBottom(); ok {
+		t.Fatal("PopBottom on empty stack should fail")
+	}
+	var zero Stack[int]
+	if _, ok := zero.PopBottom(); ok {
+		t.Fatal("PopBottom on zero value stack should fail")
+	}
+}
+
 func TestClear(t *testing.T) {
 	s := NewStack[int]()
 	for i := 0; i < 3; i++ {
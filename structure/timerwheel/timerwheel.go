@@ -0,0 +1,207 @@
+// Package timerwheel 提供了分层哈希时间轮的实现，用于管理大量的超时定时器。
+package timerwheel
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// Timer 是通过 TimerWheel.Schedule 创建的定时器句柄。
+type Timer struct {
+	fn      func()
+	expires uint64        // 到期时的绝对刻度
+	slot    *list.List    // 所在的槽，未挂在时间轮上时为 nil
+	elem    *list.Element // 在槽中的位置
+}
+
+// TimerWheel 是一个分层哈希时间轮。
+// 第 0 层每个槽代表一个刻度，第 l 层每个槽代表 slots^l 个刻度，
+// 高层的定时器在接近到期时逐层下放（cascade）到低层。
+// 每个槽是一个双向链表，因此 Schedule、Cancel 和 Reset 的时间复杂度均为 O(1)。
+// 时间只会通过 Advance 或 Run 推进，定时器回调在推进时间的 goroutine 中执行。
+type TimerWheel struct {
+	mu      sync.Mutex
+	tick    time.Duration
+	bits    uint   // 每层槽数的以 2 为底的对数
+	mask    uint64 // 每层槽数减一
+	levels  [][]*list.List
+	current uint64        // 已经过的刻度数
+	remain  time.Duration // 不足一个刻度的累计推进时间
+	size    int
+}
+
+// NewTimerWheel 创建一个时间轮。
+// tick 是时间粒度，slots 是每层的槽数（必须为 2 的幂），levels 是层数。
+// 时间轮无需下放即可覆盖的范围为 tick * slots^levels，更远的定时器会在顶层多轮等待。
+// 参数无效时会引发 panic。
+func NewTimerWheel(tick time.Duration, slots, levels int) *TimerWheel {
+	if tick <= 0 {
+		panic(fmt.Sprintf("timerwheel: non-positive tick %v", tick))
+	}
+	if slots < 2 || slots&(slots-1) != 0 {
+		panic(fmt.Sprintf("timerwheel: slots must be a power of two, got %d", slots))
+	}
+	if levels < 1 {
+		panic(fmt.Sprintf("timerwheel: levels must be positive, got %d", levels))
+	}
+
+	w := &TimerWheel{
+		tick:   tick,
+		bits:   uint(bits.TrailingZeros(uint(slots))),
+		mask:   uint64(slots - 1),
+		levels: make([][]*list.List, levels),
+	}
+	for l := range w.levels {
+		w.levels[l] = make([]*list.List, slots)
+		for s := range w.levels[l] {
+			w.levels[l][s] = list.New()
+		}
+	}
+	return w
+}
+
+// Tick 返回时间轮的时间粒度。
+func (w *TimerWheel) Tick() time.Duration {
+	return w.tick
+}
+
+// Len 返回尚未触发且未被取消的定时器数量。
+func (w *TimerWheel) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// Schedule 创建一个在 d 之后触发 fn 的定时器并返回其句柄。
+// 触发时间向上取整到刻度，因此不会早于 d；d 不为正数时在下一个刻度触发。
+func (w *TimerWheel) Schedule(d time.Duration, fn func()) *Timer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t := &Timer{fn: fn}
+	w.schedule(t, d)
+	return t
+}
+
+// Cancel 取消定时器。
+// 如果定时器已经触发或已被取消返回 false。
+func (w *TimerWheel) Cancel(t *Timer) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.remove(t)
+}
+
+// Reset 将定时器改为在 d 之后触发，已触发或已取消的定时器会被重新激活。
+// 返回定时器在重置前是否处于等待状态。
+func (w *TimerWheel) Reset(t *Timer, d time.Duration) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	active := w.remove(t)
+	w.schedule(t, d)
+	return active
+}
+
+// Advance 将时间轮推进 d，并按到期顺序执行所有到期的定时器回调。
+// 回调在锁外执行，可以在回调中调度或取消定时器。
+func (w *TimerWheel) Advance(d time.Duration) {
+	w.mu.Lock()
+	total := w.remain + d
+	ticks := total / w.tick
+	w.remain = total % w.tick
+	w.mu.Unlock()
+
+	for ; ticks > 0; ticks-- {
+		for _, fn := range w.step() {
+			fn()
+		}
+	}
+}
+
+// Run 使用真实时间每隔一个刻度推进一次时间轮，直到 ctx 被取消。
+func (w *TimerWheel) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Advance(w.tick)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step 推进一个刻度，下放高层的定时器，并返回本刻度到期的回调。
+func (w *TimerWheel) step() []func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.current++
+	// 低层转完一圈时，把上一层对应槽中的定时器下放
+	for l := 1; l < len(w.levels); l++ {
+		if w.index(w.current, l-1) != 0 {
+			break
+		}
+		// 先换上空槽再逐个重新放置，顶层的远期定时器可能被放回同一个槽
+		i := w.index(w.current, l)
+		slot := w.levels[l][i]
+		w.levels[l][i] = list.New()
+		for e := slot.Front(); e != nil; e = e.Next() {
+			w.place(e.Value.(*Timer))
+		}
+	}
+
+	slot := w.levels[0][w.index(w.current, 0)]
+	var fired []func()
+	for e := slot.Front(); e != nil; {
+		next := e.Next()
+		t := e.Value.(*Timer)
+		if t.expires <= w.current {
+			slot.Remove(e)
+			t.slot, t.elem = nil, nil
+			w.size--
+			fired = append(fired, t.fn)
+		}
+		e = next
+	}
+	return fired
+}
+
+// schedule 计算到期刻度并将定时器挂到时间轮上，调用者需持有锁。
+func (w *TimerWheel) schedule(t *Timer, d time.Duration) {
+	// 把不足一个刻度的累计时间计入，保证不会提前触发
+	ticks := uint64(max((d+w.remain+w.tick-1)/w.tick, 1))
+	t.expires = w.current + ticks
+	w.place(t)
+	w.size++
+}
+
+// place 根据剩余刻度选择层和槽并挂入定时器，调用者需持有锁。
+func (w *TimerWheel) place(t *Timer) {
+	delta := t.expires - min(t.expires, w.current)
+	level := 0
+	for level < len(w.levels)-1 && delta >= uint64(1)<<(w.bits*uint(level+1)) {
+		level++
+	}
+	t.slot = w.levels[level][w.index(t.expires, level)]
+	t.elem = t.slot.PushBack(t)
+}
+
+// remove 将定时器从所在的槽中摘除，调用者需持有锁。
+func (w *TimerWheel) remove(t *Timer) bool {
+	if t.slot == nil {
+		return false
+	}
+	t.slot.Remove(t.elem)
+	t.slot, t.elem = nil, nil
+	w.size--
+	return true
+}
+
+// index 返回刻度 tick 在第 level 层对应的槽下标。
+func (w *TimerWheel) index(tick uint64, level int) uint64 {
+	return (tick >> (w.bits * uint(level))) & w.mask
+}
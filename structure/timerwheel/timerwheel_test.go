@@ -0,0 +1,182 @@
+package timerwheel
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewTimerWheelValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		tick          time.Duration
+		slots, levels int
+	}{
+		{"zero tick", 0, 8, 1},
+		{"slots not power of two", time.Millisecond, 6, 1},
+		{"no levels", time.Millisecond, 8, 0},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", tt.name)
+				}
+			}()
+			NewTimerWheel(tt.tick, tt.slots, tt.levels)
+		}()
+	}
+}
+
+func TestScheduleFiresOnTime(t *testing.T) {
+	w := NewTimerWheel(time.Millisecond, 8, 3)
+	var fired []int
+	w.Schedule(3*time.Millisecond, func() { fired = append(fired, 3) })
+	w.Schedule(time.Millisecond, func() { fired = append(fired, 1) })
+	w.Schedule(100*time.Millisecond, func() { fired = append(fired, 100) })
+
+	w.Advance(2 * time.Millisecond)
+	if !slices.Equal(fired, []int{1}) {
+		t.Fatalf("after 2ms expected [1], got %v", fired)
+	}
+	w.Advance(time.Millisecond)
+	if !slices.Equal(fired, []int{1, 3}) {
+		t.Fatalf("after 3ms expected [1 3], got %v", fired)
+	}
+	w.Advance(96 * time.Millisecond)
+	if len(fired) != 2 {
+		t.Fatalf("100ms timer fired early: %v", fired)
+	}
+	w.Advance(time.Millisecond)
+	if !slices.Equal(fired, []int{1, 3, 100}) {
+		t.Fatalf("after 100ms expected [1 3 100], got %v", fired)
+	}
+	if w.Len() != 0 {
+		t.Fatalf("expected no pending timers, got %d", w.Len())
+	}
+}
+
+// TestRandomDelays 使用较小的时间轮覆盖多层下放和超出范围的定时器。
+func TestRandomDelays(t *testing.T) {
+	w := NewTimerWheel(time.Millisecond, 4, 2) // 无需多轮等待的范围为 16 个刻度
+	rng := rand.New(rand.NewSource(7))
+
+	const n = 2000
+	want := make([]int, n)
+	got := make([]int, n)
+	now := 0
+	for i := 0; i < n; i++ {
+		delay := rng.Intn(60) + 1
+		want[i] = now + delay
+		w.Schedule(time.Duration(delay)*time.Millisecond, func() { got[i] = now })
+		if rng.Intn(4) == 0 {
+			step := rng.Intn(5)
+			for s := 0; s < step; s++ {
+				now++
+				w.Advance(time.Millisecond)
+			}
+		}
+	}
+	for w.Len() > 0 {
+		now++
+		w.Advance(time.Millisecond)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("timer %d fired at %d, expected %d", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCancelAndReset(t *testing.T) {
+	w := NewTimerWheel(10*time.Millisecond, 16, 2)
+	fired := 0
+	timer := w.Schedule(50*time.Millisecond, func() { fired++ })
+
+	if !w.Cancel(timer) {
+		t.Fatal("Cancel of pending timer should succeed")
+	}
+	if w.Cancel(timer) {
+		t.Fatal("second Cancel should fail")
+	}
+	w.Advance(time.Second)
+	if fired != 0 {
+		t.Fatal("cancelled timer fired")
+	}
+
+	if w.Reset(timer, 20*time.Millisecond) {
+		t.Fatal("Reset of inactive timer should report false")
+	}
+	w.Advance(10 * time.Millisecond)
+	if !w.Reset(timer, 20*time.Millisecond) {
+		t.Fatal("Reset of pending timer should report true")
+	}
+	w.Advance(10 * time.Millisecond)
+	if fired != 0 {
+		t.Fatal("reset timer fired at its old deadline")
+	}
+	w.Advance(10 * time.Millisecond)
+	if fired != 1 {
+		t.Fatalf("reset timer should fire once, fired %d times", fired)
+	}
+}
+
+func TestSubTickRemainder(t *testing.T) {
+	w := NewTimerWheel(10*time.Millisecond, 8, 2)
+	w.Advance(7 * time.Millisecond)
+
+	fired := false
+	w.Schedule(10*time.Millisecond, func() { fired = true })
+	w.Advance(3 * time.Millisecond) // 到达刻度边界，但距调度只过了 3ms
+	if fired {
+		t.Fatal("timer fired before its delay elapsed")
+	}
+	w.Advance(10 * time.Millisecond)
+	if !fired {
+		t.Fatal("timer should have fired")
+	}
+}
+
+func TestScheduleFromCallback(t *testing.T) {
+	w := NewTimerWheel(time.Millisecond, 8, 2)
+	var order []string
+	w.Schedule(time.Millisecond, func() {
+		order = append(order, "first")
+		w.Schedule(time.Millisecond, func() { order = append(order, "second") })
+	})
+	w.Advance(5 * time.Millisecond)
+	if !slices.Equal(order, []string{"first", "second"}) {
+		t.Fatalf("unexpected order %v", order)
+	}
+}
+
+func TestRun(t *testing.T) {
+	w := NewTimerWheel(time.Millisecond, 8, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	fired := make(chan struct{})
+	w.Schedule(2*time.Millisecond, func() { close(fired) })
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire under Run")
+	}
+	cancel()
+	<-done
+}
+
+func BenchmarkScheduleCancel(b *testing.B) {
+	w := NewTimerWheel(time.Millisecond, 256, 4)
+	fn := func() {}
+	for i := 0; i < b.N; i++ {
+		t := w.Schedule(time.Duration(i%100000)*time.Millisecond, fn)
+		w.Cancel(t)
+	}
+}
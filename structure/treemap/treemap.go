@@ -0,0 +1,465 @@
+// Package treemap 提供了基于红黑树的泛型有序映射实现，接口风格贴近 C++ std::map。
+package treemap
+
+import (
+	"cmp"
+	"iter"
+)
+
+// node 是红黑树中的一个节点。
+type node[K, V any] struct {
+	key   K
+	value V
+	left  *node[K, V]
+	right *node[K, V]
+	red   bool // 指向该节点的链接是否为红色
+}
+
+// TreeMap 是一个按键有序的泛型映射，内部使用左倾红黑树实现。
+// 所有查找、插入和删除操作的时间复杂度均为 O(log n)。
+// 在迭代过程中修改 TreeMap 的结果是未定义的。
+type TreeMap[K, V any] struct {
+	root *node[K, V]
+	size int
+	cmp  func(a, b K) int
+}
+
+// NewTreeMap 使用自定义比较函数创建一个空的 TreeMap。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+func NewTreeMap[K, V any](cmp func(a, b K) int) *TreeMap[K, V] {
+	return &TreeMap[K, V]{cmp: cmp}
+}
+
+// NewOrderedTreeMap 创建一个按键升序排列的空 TreeMap。
+// 仅适用于实现了 cmp.Ordered 接口的键类型（如 int, float64, string 等）。
+func NewOrderedTreeMap[K cmp.Ordered, V any]() *TreeMap[K, V] {
+	return NewTreeMap[K, V](cmp.Compare[K])
+}
+
+// Len 返回 TreeMap 中键值对的数量。
+func (m *TreeMap[K, V]) Len() int {
+	return m.size
+}
+
+// IsEmpty 检查 TreeMap 是否为空。
+func (m *TreeMap[K, V]) IsEmpty() bool {
+	return m.size == 0
+}
+
+// Clear 移除 TreeMap 中的所有键值对。
+func (m *TreeMap[K, V]) Clear() {
+	m.root = nil
+	m.size = 0
+}
+
+// Put 插入一个键值对，如果键已存在则覆盖其值。
+func (m *TreeMap[K, V]) Put(key K, value V) {
+	m.root = m.put(m.root, key, value)
+	m.root.red = false
+}
+
+// put 在以 h 为根的子树中插入键值对，返回新的子树根。
+func (m *TreeMap[K, V]) put(h *node[K, V], key K, value V) *node[K, V] {
+	if h == nil {
+		m.size++
+		return &node[K, V]{key: key, value: value, red: true}
+	}
+
+	switch c := m.cmp(key, h.key); {
+	case c < 0:
+		h.left = m.put(h.left, key, value)
+	case c > 0:
+		h.right = m.put(h.right, key, value)
+	default:
+		h.value = value
+	}
+
+	return balance(h)
+}
+
+// Get 返回指定键对应的值。
+// 如果键不存在，返回零值和 false。
+func (m *TreeMap[K, V]) Get(key K) (V, bool) {
+	if n := m.find(key); n != nil {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Contains 检查 TreeMap 是否包含指定的键。
+func (m *TreeMap[K, V]) Contains(key K) bool {
+	return m.find(key) != nil
+}
+
+// find 返回键对应的节点，不存在时返回 nil。
+func (m *TreeMap[K, V]) find(key K) *node[K, V] {
+	n := m.root
+	for n != nil {
+		switch c := m.cmp(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Delete 移除指定键及其对应的值。
+// 如果键不存在返回 false。
+func (m *TreeMap[K, V]) Delete(key K) bool {
+	if !m.Contains(key) {
+		return false
+	}
+
+	// 根节点的两个子链接都为黑色时，先将根染红以便向下借节点
+	if !isRed(m.root.left) && !isRed(m.root.right) {
+		m.root.red = true
+	}
+	m.root = m.delete(m.root, key)
+	if m.root != nil {
+		m.root.red = false
+	}
+	m.size--
+	return true
+}
+
+// delete 从以 h 为根的子树中删除键，调用者需保证键存在。
+func (m *TreeMap[K, V]) delete(h *node[K, V], key K) *node[K, V] {
+	if m.cmp(key, h.key) < 0 {
+		if !isRed(h.left) && !isRed(h.left.left) {
+			h = moveRedLeft(h)
+		}
+		h.left = m.delete(h.left, key)
+	} else {
+		if isRed(h.left) {
+			h = rotateRight(h)
+		}
+		if m.cmp(key, h.key) == 0 && h.right == nil {
+			return nil
+		}
+		if !isRed(h.right) && !isRed(h.right.left) {
+			h = moveRedRight(h)
+		}
+		if m.cmp(key, h.key) == 0 {
+			// 用右子树的最小节点替换当前节点
+			successor := minNode(h.right)
+			h.key = successor.key
+			h.value = successor.value
+			h.right = deleteMin(h.right)
+		} else {
+			h.right = m.delete(h.right, key)
+		}
+	}
+	return balance(h)
+}
+
+// Min 返回最小的键及其值。
+// 如果 TreeMap 为空，返回零值和 false。
+func (m *TreeMap[K, V]) Min() (K, V, bool) {
+	if m.root == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	n := minNode(m.root)
+	return n.key, n.value, true
+}
+
+// Max 返回最大的键及其值。
+// 如果 TreeMap 为空，返回零值和 false。
+func (m *TreeMap[K, V]) Max() (K, V, bool) {
+	if m.root == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	n := m.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.value, true
+}
+
+// Floor 返回小于或等于 key 的最大键及其值。
+// 如果不存在这样的键，返回零值和 false。
+func (m *TreeMap[K, V]) Floor(key K) (K, V, bool) {
+	return entry(m.below(key, true))
+}
+
+// Lower 返回严格小于 key 的最大键及其值。
+// 如果不存在这样的键，返回零值和 false。
+func (m *TreeMap[K, V]) Lower(key K) (K, V, bool) {
+	return entry(m.below(key, false))
+}
+
+// Ceiling 返回大于或等于 key 的最小键及其值。
+// 如果不存在这样的键，返回零值和 false。
+func (m *TreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	return entry(m.above(key, true))
+}
+
+// Higher 返回严格大于 key 的最小键及其值。
+// 如果不存在这样的键，返回零值和 false。
+func (m *TreeMap[K, V]) Higher(key K) (K, V, bool) {
+	return entry(m.above(key, false))
+}
+
+// below 返回小于 key（inclusive 为 true 时包括等于）的最大节点。
+func (m *TreeMap[K, V]) below(key K, inclusive bool) *node[K, V] {
+	var best *node[K, V]
+	n := m.root
+	for n != nil {
+		c := m.cmp(key, n.key)
+		if c > 0 || (inclusive && c == 0) {
+			best = n
+			if c == 0 {
+				break
+			}
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return best
+}
+
+// above 返回大于 key（inclusive 为 true 时包括等于）的最小节点。
+func (m *TreeMap[K, V]) above(key K, inclusive bool) *node[K, V] {
+	var best *node[K, V]
+	n := m.root
+	for n != nil {
+		c := m.cmp(key, n.key)
+		if c < 0 || (inclusive && c == 0) {
+			best = n
+			if c == 0 {
+				break
+			}
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return best
+}
+
+// All 返回一个按键升序遍历所有键值对的迭代器。
+func (m *TreeMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, yield)
+	}
+}
+
+// Backward 返回一个按键降序遍历所有键值对的迭代器。
+func (m *TreeMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		descend(m.root, yield)
+	}
+}
+
+// Keys 返回一个按升序遍历所有键的迭代器。
+func (m *TreeMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		ascend(m.root, func(k K, _ V) bool { return yield(k) })
+	}
+}
+
+// Values 返回一个按键升序遍历所有值的迭代器。
+func (m *TreeMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		ascend(m.root, func(_ K, v V) bool { return yield(v) })
+	}
+}
+
+// Range 返回一个按键升序遍历区间 [lo, hi) 内键值对的迭代器。
+func (m *TreeMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.ascendRange(m.root, lo, hi, yield)
+	}
+}
+
+// RangeBackward 返回一个按键降序遍历区间 [lo, hi) 内键值对的迭代器。
+func (m *TreeMap[K, V]) RangeBackward(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.descendRange(m.root, lo, hi, yield)
+	}
+}
+
+// ascendRange 中序遍历子树中位于 [lo, hi) 的节点，yield 返回 false 时停止。
+func (m *TreeMap[K, V]) ascendRange(n *node[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := m.cmp(lo, n.key) <= 0
+	belowHi := m.cmp(n.key, hi) < 0
+	if aboveLo && !m.ascendRange(n.left, lo, hi, yield) {
+		return false
+	}
+	if aboveLo && belowHi && !yield(n.key, n.value) {
+		return false
+	}
+	if belowHi {
+		return m.ascendRange(n.right, lo, hi, yield)
+	}
+	return true
+}
+
+// descendRange 逆中序遍历子树中位于 [lo, hi) 的节点，yield 返回 false 时停止。
+func (m *TreeMap[K, V]) descendRange(n *node[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := m.cmp(lo, n.key) <= 0
+	belowHi := m.cmp(n.key, hi) < 0
+	if belowHi && !m.descendRange(n.right, lo, hi, yield) {
+		return false
+	}
+	if aboveLo && belowHi && !yield(n.key, n.value) {
+		return false
+	}
+	if aboveLo {
+		return m.descendRange(n.left, lo, hi, yield)
+	}
+	return true
+}
+
+// Clone 创建并返回 TreeMap 的一个副本，键和值按赋值语义复制。
+func (m *TreeMap[K, V]) Clone() *TreeMap[K, V] {
+	return &TreeMap[K, V]{
+		root: cloneNode(m.root),
+		size: m.size,
+		cmp:  m.cmp,
+	}
+}
+
+// cloneNode 递归复制以 n 为根的子树。
+func cloneNode[K, V any](n *node[K, V]) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+	return &node[K, V]{
+		key:   n.key,
+		value: n.value,
+		left:  cloneNode(n.left),
+		right: cloneNode(n.right),
+		red:   n.red,
+	}
+}
+
+// ascend 中序遍历子树，yield 返回 false 时停止并返回 false。
+func ascend[K, V any](n *node[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return ascend(n.left, yield) && yield(n.key, n.value) && ascend(n.right, yield)
+}
+
+// descend 逆中序遍历子树，yield 返回 false 时停止并返回 false。
+func descend[K, V any](n *node[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return descend(n.right, yield) && yield(n.key, n.value) && descend(n.left, yield)
+}
+
+// entry 将节点拆分为键、值和是否存在的标志。
+func entry[K, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return n.key, n.value, true
+}
+
+// isRed 判断节点是否为红色，nil 视为黑色。
+func isRed[K, V any](n *node[K, V]) bool {
+	return n != nil && n.red
+}
+
+// rotateLeft 将右倾的红链接旋转为左倾。
+func rotateLeft[K, V any](h *node[K, V]) *node[K, V] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.red = h.red
+	h.red = true
+	return x
+}
+
+// rotateRight 将左倾的红链接旋转为右倾。
+func rotateRight[K, V any](h *node[K, V]) *node[K, V] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.red = h.red
+	h.red = true
+	return x
+}
+
+// flipColors 翻转节点及其两个子节点的颜色。
+func flipColors[K, V any](h *node[K, V]) {
+	h.red = !h.red
+	h.left.red = !h.left.red
+	h.right.red = !h.right.red
+}
+
+// moveRedLeft 假设 h 为红色且 h.left 与 h.left.left 均为黑色，
+// 将 h.left 或其某个子节点染红。
+func moveRedLeft[K, V any](h *node[K, V]) *node[K, V] {
+	flipColors(h)
+	if isRed(h.right.left) {
+		h.right = rotateRight(h.right)
+		h = rotateLeft(h)
+		flipColors(h)
+	}
+	return h
+}
+
+// moveRedRight 假设 h 为红色且 h.right 与 h.right.left 均为黑色，
+// 将 h.right 或其某个子节点染红。
+func moveRedRight[K, V any](h *node[K, V]) *node[K, V] {
+	flipColors(h)
+	if isRed(h.left.left) {
+		h = rotateRight(h)
+		flipColors(h)
+	}
+	return h
+}
+
+// balance 恢复以 h 为根的子树的红黑树性质。
+func balance[K, V any](h *node[K, V]) *node[K, V] {
+	if isRed(h.right) && !isRed(h.left) {
+		h = rotateLeft(h)
+	}
+	if isRed(h.left) && isRed(h.left.left) {
+		h = rotateRight(h)
+	}
+	if isRed(h.left) && isRed(h.right) {
+		flipColors(h)
+	}
+	return h
+}
+
+// minNode 返回子树中最小的节点。
+func minNode[K, V any](h *node[K, V]) *node[K, V] {
+	for h.left != nil {
+		h = h.left
+	}
+	return h
+}
+
+// deleteMin 删除子树中最小的节点并返回新的子树根。
+func deleteMin[K, V any](h *node[K, V]) *node[K, V] {
+	if h.left == nil {
+		return nil
+	}
+	if !isRed(h.left) && !isRed(h.left.left) {
+		h = moveRedLeft(h)
+	}
+	h.left = deleteMin(h.left)
+	return balance(h)
+}
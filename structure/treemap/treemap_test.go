@@ -0,0 +1,263 @@
+package treemap
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkInvariants 校验左倾红黑树的结构性质，返回子树的黑高。
+func checkInvariants[K, V any](t *testing.T, m *TreeMap[K, V], n *node[K, V]) int {
+	t.Helper()
+	if n == nil {
+		return 1
+	}
+	if isRed(n.right) {
+		t.Fatal("right-leaning red link found")
+	}
+	if isRed(n) && isRed(n.left) {
+		t.Fatal("two consecutive red links found")
+	}
+	if n.left != nil && m.cmp(n.left.key, n.key) >= 0 {
+		t.Fatal("left child is not smaller than parent")
+	}
+	if n.right != nil && m.cmp(n.right.key, n.key) <= 0 {
+		t.Fatal("right child is not greater than parent")
+	}
+	lh := checkInvariants(t, m, n.left)
+	rh := checkInvariants(t, m, n.right)
+	if lh != rh {
+		t.Fatalf("unbalanced black height: %d vs %d", lh, rh)
+	}
+	if !isRed(n) {
+		lh++
+	}
+	return lh
+}
+
+func collectKeys[K, V any](seq func(func(K, V) bool)) []K {
+	var keys []K
+	for k := range seq {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestNewTreeMap(t *testing.T) {
+	m := NewOrderedTreeMap[int, string]()
+	if m.Len() != 0 || !m.IsEmpty() {
+		t.Fatalf("expected empty map, got len %d", m.Len())
+	}
+	if _, _, ok := m.Min(); ok {
+		t.Fatal("Min on empty map should fail")
+	}
+	if _, _, ok := m.Max(); ok {
+		t.Fatal("Max on empty map should fail")
+	}
+	if m.Delete(1) {
+		t.Fatal("Delete on empty map should fail")
+	}
+}
+
+func TestPutGetDelete(t *testing.T) {
+	m := NewOrderedTreeMap[string, int]()
+	m.Put("b", 2)
+	m.Put("a", 1)
+	m.Put("c", 3)
+	m.Put("a", 10)
+
+	if m.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", m.Len())
+	}
+	if v, ok := m.Get("a"); !ok || v != 10 {
+		t.Fatalf("Get(a) expected (10,true), got (%d,%v)", v, ok)
+	}
+	if _, ok := m.Get("z"); ok {
+		t.Fatal("Get of missing key should fail")
+	}
+	if !m.Delete("b") || m.Contains("b") || m.Len() != 2 {
+		t.Fatal("Delete(b) did not remove the key")
+	}
+	if m.Delete("b") {
+		t.Fatal("second Delete(b) should fail")
+	}
+	m.Clear()
+	if !m.IsEmpty() {
+		t.Fatal("map should be empty after Clear")
+	}
+}
+
+func TestRandomOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := NewOrderedTreeMap[int, int]()
+	ref := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		k := rng.Intn(1000)
+		if rng.Intn(3) == 0 {
+			_, want := ref[k]
+			delete(ref, k)
+			if got := m.Delete(k); got != want {
+				t.Fatalf("Delete(%d) expected %v, got %v", k, want, got)
+			}
+		} else {
+			ref[k] = i
+			m.Put(k, i)
+		}
+		if m.Len() != len(ref) {
+			t.Fatalf("expected len %d, got %d", len(ref), m.Len())
+		}
+		if i%1000 == 0 {
+			checkInvariants(t, m, m.root)
+		}
+	}
+	checkInvariants(t, m, m.root)
+
+	want := make([]int, 0, len(ref))
+	for k := range ref {
+		want = append(want, k)
+	}
+	slices.Sort(want)
+	if got := collectKeys(m.All()); !slices.Equal(got, want) {
+		t.Fatal("All did not yield keys in ascending order")
+	}
+	for k, v := range m.All() {
+		if ref[k] != v {
+			t.Fatalf("value for key %d expected %d, got %d", k, ref[k], v)
+		}
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	m := NewOrderedTreeMap[int, string]()
+	for _, k := range []int{5, 3, 8, 1, 9} {
+		m.Put(k, "")
+	}
+	if k, _, ok := m.Min(); !ok || k != 1 {
+		t.Fatalf("Min expected 1, got %d", k)
+	}
+	if k, _, ok := m.Max(); !ok || k != 9 {
+		t.Fatalf("Max expected 9, got %d", k)
+	}
+}
+
+func TestFloorCeilingLowerHigher(t *testing.T) {
+	m := NewOrderedTreeMap[int, int]()
+	for _, k := range []int{10, 20, 30, 40} {
+		m.Put(k, k*10)
+	}
+
+	tests := []struct {
+		name   string
+		fn     func(int) (int, int, bool)
+		key    int
+		want   int
+		wantOK bool
+	}{
+		{"Floor exact", m.Floor, 20, 20, true},
+		{"Floor between", m.Floor, 25, 20, true},
+		{"Floor below min", m.Floor, 5, 0, false},
+		{"Ceiling exact", m.Ceiling, 30, 30, true},
+		{"Ceiling between", m.Ceiling, 25, 30, true},
+		{"Ceiling above max", m.Ceiling, 45, 0, false},
+		{"Lower exact", m.Lower, 20, 10, true},
+		{"Lower min", m.Lower, 10, 0, false},
+		{"Higher exact", m.Higher, 30, 40, true},
+		{"Higher max", m.Higher, 40, 0, false},
+	}
+	for _, tt := range tests {
+		k, v, ok := tt.fn(tt.key)
+		if ok != tt.wantOK || k != tt.want {
+			t.Errorf("%s(%d) expected (%d,%v), got (%d,%v)", tt.name, tt.key, tt.want, tt.wantOK, k, ok)
+		}
+		if ok && v != k*10 {
+			t.Errorf("%s(%d) returned wrong value %d", tt.name, tt.key, v)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	m := NewOrderedTreeMap[int, int]()
+	for i := 0; i < 100; i++ {
+		m.Put(i*2, i)
+	}
+
+	if got := collectKeys(m.Range(10, 20)); !slices.Equal(got, []int{10, 12, 14, 16, 18}) {
+		t.Fatalf("Range(10,20) unexpected result %v", got)
+	}
+	if got := collectKeys(m.Range(11, 19)); !slices.Equal(got, []int{12, 14, 16, 18}) {
+		t.Fatalf("Range(11,19) unexpected result %v", got)
+	}
+	if got := collectKeys(m.RangeBackward(10, 20)); !slices.Equal(got, []int{18, 16, 14, 12, 10}) {
+		t.Fatalf("RangeBackward(10,20) unexpected result %v", got)
+	}
+	if got := collectKeys(m.Range(20, 10)); len(got) != 0 {
+		t.Fatalf("Range with lo > hi should be empty, got %v", got)
+	}
+
+	var first []int
+	for k := range m.Range(0, 200) {
+		first = append(first, k)
+		if len(first) == 3 {
+			break
+		}
+	}
+	if !slices.Equal(first, []int{0, 2, 4}) {
+		t.Fatalf("Range should stop on break, got %v", first)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	m := NewTreeMap[string, int](func(a, b string) int { return cmp.Compare(b, a) })
+	m.Put("a", 1)
+	m.Put("c", 3)
+	m.Put("b", 2)
+
+	if got := slices.Collect(m.Keys()); !slices.Equal(got, []string{"c", "b", "a"}) {
+		t.Fatalf("Keys with reversed comparator unexpected result %v", got)
+	}
+	if got := slices.Collect(m.Values()); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Fatalf("Values unexpected result %v", got)
+	}
+	if got := collectKeys(m.Backward()); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatalf("Backward unexpected result %v", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	m := NewOrderedTreeMap[int, int]()
+	for i := 0; i < 10; i++ {
+		m.Put(i, i)
+	}
+	c := m.Clone()
+	c.Put(100, 100)
+	c.Delete(0)
+	c.Put(1, 99)
+
+	if m.Len() != 10 || !m.Contains(0) || m.Contains(100) {
+		t.Fatal("modifying clone should not affect original")
+	}
+	if v, _ := m.Get(1); v != 1 {
+		t.Fatalf("original value changed to %d", v)
+	}
+	checkInvariants(t, c, c.root)
+}
+
+func BenchmarkPut(b *testing.B) {
+	m := NewOrderedTreeMap[int, int]()
+	for i := 0; i < b.N; i++ {
+		m.Put(i, i)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	m := NewOrderedTreeMap[int, int]()
+	for i := 0; i < 1<<16; i++ {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(i & (1<<16 - 1))
+	}
+}
@@ -0,0 +1,247 @@
+// Package treeset 提供了基于 TreeMap 的泛型有序集合实现，接口风格贴近 C++ std::set。
+package treeset
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/Repeater11/go-template/structure/treemap"
+)
+
+// TreeSet 是一个按比较函数有序且不重复的泛型集合。
+// 所有查找、插入和删除操作的时间复杂度均为 O(log n)。
+type TreeSet[T any] struct {
+	tree *treemap.TreeMap[T, struct{}]
+	cmp  func(a, b T) int
+}
+
+// NewTreeSet 使用自定义比较函数创建一个 TreeSet。
+// cmp 函数应返回负数、零或正数，分别表示 a < b、a == b 或 a > b。
+// 可选地，可以传入初始元素来填充 TreeSet。
+func NewTreeSet[T any](cmp func(a, b T) int, elements ...T) *TreeSet[T] {
+	s := &TreeSet[T]{
+		tree: treemap.NewTreeMap[T, struct{}](cmp),
+		cmp:  cmp,
+	}
+	s.Add(elements...)
+	return s
+}
+
+// NewOrderedTreeSet 创建一个按升序排列的 TreeSet。
+// 仅适用于实现了 cmp.Ordered 接口的类型（如 int, float64, string 等）。
+func NewOrderedTreeSet[T cmp.Ordered](elements ...T) *TreeSet[T] {
+	return NewTreeSet(cmp.Compare[T], elements...)
+}
+
+// Len 返回 TreeSet 中元素的数量。
+func (s *TreeSet[T]) Len() int {
+	return s.tree.Len()
+}
+
+// IsEmpty 检查 TreeSet 是否为空。
+func (s *TreeSet[T]) IsEmpty() bool {
+	return s.tree.IsEmpty()
+}
+
+// Clear 移除 TreeSet 中的所有元素。
+func (s *TreeSet[T]) Clear() {
+	s.tree.Clear()
+}
+
+// Add 向 TreeSet 中添加一个或多个元素，已存在的元素会被忽略。
+func (s *TreeSet[T]) Add(elements ...T) {
+	for _, elem := range elements {
+		s.tree.Put(elem, struct{}{})
+	}
+}
+
+// Remove 从 TreeSet 中移除一个或多个元素，不存在的元素会被忽略。
+func (s *TreeSet[T]) Remove(elements ...T) {
+	for _, elem := range elements {
+		s.tree.Delete(elem)
+	}
+}
+
+// Contains 检查 TreeSet 是否包含指定的元素。
+func (s *TreeSet[T]) Contains(elem T) bool {
+	return s.tree.Contains(elem)
+}
+
+// First 返回最小的元素。
+// 如果 TreeSet 为空，返回零值和 false。
+func (s *TreeSet[T]) First() (T, bool) {
+	elem, _, ok := s.tree.Min()
+	return elem, ok
+}
+
+// Last 返回最大的元素。
+// 如果 TreeSet 为空，返回零值和 false。
+func (s *TreeSet[T]) Last() (T, bool) {
+	elem, _, ok := s.tree.Max()
+	return elem, ok
+}
+
+// PopFirst 移除并返回最小的元素。
+// 如果 TreeSet 为空，返回零值和 false。
+func (s *TreeSet[T]) PopFirst() (T, bool) {
+	elem, ok := s.First()
+	if ok {
+		s.tree.Delete(elem)
+	}
+	return elem, ok
+}
+
+// PopLast 移除并返回最大的元素。
+// 如果 TreeSet 为空，返回零值和 false。
+func (s *TreeSet[T]) PopLast() (T, bool) {
+	elem, ok := s.Last()
+	if ok {
+		s.tree.Delete(elem)
+	}
+	return elem, ok
+}
+
+// Floor 返回小于或等于 elem 的最大元素。
+// 如果不存在这样的元素，返回零值和 false。
+func (s *TreeSet[T]) Floor(elem T) (T, bool) {
+	result, _, ok := s.tree.Floor(elem)
+	return result, ok
+}
+
+// Ceiling 返回大于或等于 elem 的最小元素。
+// 如果不存在这样的元素，返回零值和 false。
+func (s *TreeSet[T]) Ceiling(elem T) (T, bool) {
+	result, _, ok := s.tree.Ceiling(elem)
+	return result, ok
+}
+
+// Lower 返回严格小于 elem 的最大元素。
+// 如果不存在这样的元素，返回零值和 false。
+func (s *TreeSet[T]) Lower(elem T) (T, bool) {
+	result, _, ok := s.tree.Lower(elem)
+	return result, ok
+}
+
+// Higher 返回严格大于 elem 的最小元素。
+// 如果不存在这样的元素，返回零值和 false。
+func (s *TreeSet[T]) Higher(elem T) (T, bool) {
+	result, _, ok := s.tree.Higher(elem)
+	return result, ok
+}
+
+// All 返回一个按升序遍历所有元素的迭代器。
+func (s *TreeSet[T]) All() iter.Seq[T] {
+	return s.tree.Keys()
+}
+
+// Backward 返回一个按降序遍历所有元素的迭代器。
+func (s *TreeSet[T]) Backward() iter.Seq[T] {
+	return keys(s.tree.Backward())
+}
+
+// Range 返回一个按升序遍历区间 [lo, hi) 内元素的迭代器。
+func (s *TreeSet[T]) Range(lo, hi T) iter.Seq[T] {
+	return keys(s.tree.Range(lo, hi))
+}
+
+// RangeBackward 返回一个按降序遍历区间 [lo, hi) 内元素的迭代器。
+func (s *TreeSet[T]) RangeBackward(lo, hi T) iter.Seq[T] {
+	return keys(s.tree.RangeBackward(lo, hi))
+}
+
+// ToSlice 以升序返回 TreeSet 中的所有元素。
+func (s *TreeSet[T]) ToSlice() []T {
+	result := make([]T, 0, s.Len())
+	for elem := range s.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// Clone 创建并返回 TreeSet 的一个副本。
+func (s *TreeSet[T]) Clone() *TreeSet[T] {
+	return &TreeSet[T]{
+		tree: s.tree.Clone(),
+		cmp:  s.cmp,
+	}
+}
+
+// Union 返回一个新的 TreeSet，包含 s 与 other 中的所有元素。
+// 结果使用 s 的比较函数排序。
+func (s *TreeSet[T]) Union(other *TreeSet[T]) *TreeSet[T] {
+	result := s.Clone()
+	for elem := range other.All() {
+		result.tree.Put(elem, struct{}{})
+	}
+	return result
+}
+
+// Intersection 返回一个新的 TreeSet，包含同时存在于 s 与 other 中的元素。
+// 结果使用 s 的比较函数排序。
+func (s *TreeSet[T]) Intersection(other *TreeSet[T]) *TreeSet[T] {
+	result := NewTreeSet(s.cmp)
+	for elem := range s.All() {
+		if other.Contains(elem) {
+			result.tree.Put(elem, struct{}{})
+		}
+	}
+	return result
+}
+
+// Difference 返回一个新的 TreeSet，包含存在于 s 但不存在于 other 中的元素。
+// 结果使用 s 的比较函数排序。
+func (s *TreeSet[T]) Difference(other *TreeSet[T]) *TreeSet[T] {
+	result := NewTreeSet(s.cmp)
+	for elem := range s.All() {
+		if !other.Contains(elem) {
+			result.tree.Put(elem, struct{}{})
+		}
+	}
+	return result
+}
+
+// SymmetricDifference 返回一个新的 TreeSet，包含只存在于 s 或 other 其中之一的元素。
+// 结果使用 s 的比较函数排序。
+func (s *TreeSet[T]) SymmetricDifference(other *TreeSet[T]) *TreeSet[T] {
+	result := s.Difference(other)
+	for elem := range other.All() {
+		if !s.Contains(elem) {
+			result.tree.Put(elem, struct{}{})
+		}
+	}
+	return result
+}
+
+// IsSubset 检查 s 是否为 other 的子集。
+func (s *TreeSet[T]) IsSubset(other *TreeSet[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for elem := range s.All() {
+		if !other.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset 检查 s 是否为 other 的超集。
+func (s *TreeSet[T]) IsSuperset(other *TreeSet[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal 检查两个 TreeSet 是否包含完全相同的元素。
+func (s *TreeSet[T]) Equal(other *TreeSet[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// keys 将键值迭代器转换为只包含键的迭代器。
+func keys[T any](seq iter.Seq2[T, struct{}]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for elem := range seq {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
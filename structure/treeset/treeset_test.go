@@ -0,0 +1,182 @@
+package treeset
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestNewTreeSet(t *testing.T) {
+	s := NewOrderedTreeSet[int]()
+	if s.Len() != 0 || !s.IsEmpty() {
+		t.Fatalf("expected empty set, got len %d", s.Len())
+	}
+
+	s = NewOrderedTreeSet(3, 1, 2, 3, 1)
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected [1 2 3], got %v", got)
+	}
+}
+
+func TestAddRemoveContains(t *testing.T) {
+	s := NewOrderedTreeSet[string]()
+	s.Add("b", "a", "c")
+	s.Add("a")
+	if s.Len() != 3 {
+		t.Fatalf("expected len 3, got %d", s.Len())
+	}
+	s.Remove("b", "missing")
+	if s.Contains("b") || !s.Contains("a") || s.Len() != 2 {
+		t.Fatalf("unexpected contents after Remove: %v", s.ToSlice())
+	}
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Fatal("set should be empty after Clear")
+	}
+}
+
+func TestFirstLastPop(t *testing.T) {
+	s := NewOrderedTreeSet(5, 1, 9, 3)
+
+	if v, ok := s.First(); !ok || v != 1 {
+		t.Fatalf("First expected (1,true), got (%d,%v)", v, ok)
+	}
+	if v, ok := s.Last(); !ok || v != 9 {
+		t.Fatalf("Last expected (9,true), got (%d,%v)", v, ok)
+	}
+	if v, ok := s.PopFirst(); !ok || v != 1 {
+		t.Fatalf("PopFirst expected (1,true), got (%d,%v)", v, ok)
+	}
+	if v, ok := s.PopLast(); !ok || v != 9 {
+		t.Fatalf("PopLast expected (9,true), got (%d,%v)", v, ok)
+	}
+	if got := s.ToSlice(); !slices.Equal(got, []int{3, 5}) {
+		t.Fatalf("expected [3 5] after pops, got %v", got)
+	}
+
+	s.Clear()
+	if _, ok := s.First(); ok {
+		t.Fatal("First on empty set should fail")
+	}
+	if _, ok := s.PopFirst(); ok {
+		t.Fatal("PopFirst on empty set should fail")
+	}
+	if _, ok := s.PopLast(); ok {
+		t.Fatal("PopLast on empty set should fail")
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	s := NewOrderedTreeSet(10, 20, 30)
+
+	check := func(name string, v int, ok bool, want int, wantOK bool) {
+		t.Helper()
+		if v != want || ok != wantOK {
+			t.Errorf("%s expected (%d,%v), got (%d,%v)", name, want, wantOK, v, ok)
+		}
+	}
+
+	v, ok := s.Floor(25)
+	check("Floor(25)", v, ok, 20, true)
+	v, ok = s.Floor(5)
+	check("Floor(5)", v, ok, 0, false)
+	v, ok = s.Ceiling(25)
+	check("Ceiling(25)", v, ok, 30, true)
+	v, ok = s.Ceiling(30)
+	check("Ceiling(30)", v, ok, 30, true)
+	v, ok = s.Lower(20)
+	check("Lower(20)", v, ok, 10, true)
+	v, ok = s.Higher(30)
+	check("Higher(30)", v, ok, 0, false)
+}
+
+func TestRangeIteration(t *testing.T) {
+	s := NewOrderedTreeSet[int]()
+	for i := 0; i < 10; i++ {
+		s.Add(i)
+	}
+
+	if got := slices.Collect(s.Range(3, 7)); !slices.Equal(got, []int{3, 4, 5, 6}) {
+		t.Fatalf("Range(3,7) unexpected result %v", got)
+	}
+	if got := slices.Collect(s.RangeBackward(3, 7)); !slices.Equal(got, []int{6, 5, 4, 3}) {
+		t.Fatalf("RangeBackward(3,7) unexpected result %v", got)
+	}
+	if got := slices.Collect(s.Backward()); !slices.Equal(got, []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}) {
+		t.Fatalf("Backward unexpected result %v", got)
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := NewOrderedTreeSet(1, 2, 3, 4)
+	b := NewOrderedTreeSet(3, 4, 5)
+
+	tests := []struct {
+		name string
+		got  *TreeSet[int]
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+	}
+	for _, tt := range tests {
+		if got := tt.got.ToSlice(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+	if got := a.ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("set algebra should not modify operands, got %v", got)
+	}
+
+	if !NewOrderedTreeSet(3, 4).IsSubset(a) || a.IsSubset(b) {
+		t.Fatal("IsSubset returned unexpected result")
+	}
+	if !a.IsSuperset(NewOrderedTreeSet(1)) {
+		t.Fatal("IsSuperset returned unexpected result")
+	}
+	if !a.Equal(NewOrderedTreeSet(4, 3, 2, 1)) || a.Equal(b) {
+		t.Fatal("Equal returned unexpected result")
+	}
+}
+
+func TestCustomComparator(t *testing.T) {
+	type player struct {
+		name  string
+		score int
+	}
+	byScoreDesc := func(a, b player) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.name, b.name)
+	}
+
+	s := NewTreeSet(byScoreDesc,
+		player{"alice", 30},
+		player{"bob", 50},
+		player{"carol", 30},
+	)
+	top, _ := s.First()
+	if top.name != "bob" {
+		t.Fatalf("expected bob on top, got %s", top.name)
+	}
+	var names []string
+	for p := range s.All() {
+		names = append(names, p.name)
+	}
+	if !slices.Equal(names, []string{"bob", "alice", "carol"}) {
+		t.Fatalf("unexpected leaderboard order %v", names)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := NewOrderedTreeSet(1, 2, 3)
+	c := s.Clone()
+	c.Add(4)
+	c.Remove(1)
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("modifying clone should not affect original, got %v", got)
+	}
+}
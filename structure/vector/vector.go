@@ -4,6 +4,7 @@ package vector
 import (
 	"cmp"
 	"slices"
+	"sort"
 )
 
 // Vector 是一个通用的动态数组实现。
@@ -221,3 +222,73 @@ func Sort[T cmp.Ordered](v *Vector[T]) {
 func Equal[T comparable](v1, v2 *Vector[T]) bool {
 	return slices.Equal(v1.data, v2.data)
 }
+
+// LowerBound 返回有序 Vector 中第一个不小于 x 的元素的索引，不存在时返回 Len()。
+// Vector 必须已按升序排列，语义与 C++ std::lower_bound 一致。
+func LowerBound[T cmp.Ordered](v *Vector[T], x T) int {
+	i, _ := slices.BinarySearch(v.data, x)
+	return i
+}
+
+// UpperBound 返回有序 Vector 中第一个大于 x 的元素的索引，不存在时返回 Len()。
+// Vector 必须已按升序排列，语义与 C++ std::upper_bound 一致。
+func UpperBound[T cmp.Ordered](v *Vector[T], x T) int {
+	return v.UpperBoundFunc(x, cmp.Compare[T])
+}
+
+// EqualRange 返回有序 Vector 中与 x 相等的元素所在的索引范围 [begin, end)。
+// 如果不存在相等的元素，begin == end 且为 x 应当插入的位置。
+func EqualRange[T cmp.Ordered](v *Vector[T], x T) (begin, end int) {
+	return v.EqualRangeFunc(x, cmp.Compare[T])
+}
+
+// BinarySearch 在有序 Vector 中查找 x，返回第一个与 x 相等的元素的索引。
+// 如果不存在，返回 x 应当插入的位置和 false。
+func BinarySearch[T cmp.Ordered](v *Vector[T], x T) (int, bool) {
+	return slices.BinarySearch(v.data, x)
+}
+
+// InsertSorted 将 x 插入有序 Vector 并保持有序，返回插入位置。
+// 与 x 相等的元素已存在时，x 被插入到它们之后。
+func InsertSorted[T cmp.Ordered](v *Vector[T], x T) int {
+	return v.InsertSortedFunc(x, cmp.Compare[T])
+}
+
+// LowerBoundFunc 使用自定义比较函数返回第一个不小于 x 的元素的索引，不存在时返回 Len()。
+// Vector 必须已按 cmp 排列。
+func (v *Vector[T]) LowerBoundFunc(x T, cmp func(a, b T) int) int {
+	i, _ := slices.BinarySearchFunc(v.data, x, cmp)
+	return i
+}
+
+// UpperBoundFunc 使用自定义比较函数返回第一个大于 x 的元素的索引，不存在时返回 Len()。
+// Vector 必须已按 cmp 排列。
+func (v *Vector[T]) UpperBoundFunc(x T, cmp func(a, b T) int) int {
+	return sort.Search(len(v.data), func(i int) bool {
+		return cmp(v.data[i], x) > 0
+	})
+}
+
+// EqualRangeFunc 使用自定义比较函数返回与 x 相等的元素所在的索引范围 [begin, end)。
+// Vector 必须已按 cmp 排列。
+func (v *Vector[T]) EqualRangeFunc(x T, cmp func(a, b T) int) (begin, end int) {
+	begin = v.LowerBoundFunc(x, cmp)
+	end = begin + sort.Search(len(v.data)-begin, func(i int) bool {
+		return cmp(v.data[begin+i], x) > 0
+	})
+	return begin, end
+}
+
+// BinarySearchFunc 使用自定义比较函数查找 x，返回第一个与 x 相等的元素的索引。
+// 如果不存在，返回 x 应当插入的位置和 false。Vector 必须已按 cmp 排列。
+func (v *Vector[T]) BinarySearchFunc(x T, cmp func(a, b T) int) (int, bool) {
+	return slices.BinarySearchFunc(v.data, x, cmp)
+}
+
+// InsertSortedFunc 使用自定义比较函数将 x 插入有序 Vector 并保持有序，返回插入位置。
+// 与 x 相等的元素已存在时，x 被插入到它们之后。Vector 必须已按 cmp 排列。
+func (v *Vector[T]) InsertSortedFunc(x T, cmp func(a, b T) int) int {
+	i := v.UpperBoundFunc(x, cmp)
+	v.data = slices.Insert(v.data, i, x)
+	return i
+}
@@ -269,3 +269,83 @@ func TestReverse(t *testing.T) {
 		}
 	}
 }
+
+func TestBounds(t *testing.T) {
+	v := NewVector(1, 3, 3, 3, 5, 7)
+	tests := []struct {
+		x            int
+		lower, upper int
+		found        bool
+	}{
+		{0, 0, 0, false},
+		{1, 0, 1, true},
+		{3, 1, 4, true},
+		{4, 4, 4, false},
+		{7, 5, 6, true},
+		{8, 6, 6, false},
+	}
+	for _, tt := range tests {
+		if got := LowerBound(v, tt.x); got != tt.lower {
+			t.Errorf("LowerBound(%d) expected %d, got %d", tt.x, tt.lower, got)
+		}
+		if got := UpperBound(v, tt.x); got != tt.upper {
+			t.Errorf("UpperBound(%d) expected %d, got %d", tt.x, tt.upper, got)
+		}
+		if begin, end := EqualRange(v, tt.x); begin != tt.lower || end != tt.upper {
+			t.Errorf("EqualRange(%d) expected [%d,%d), got [%d,%d)", tt.x, tt.lower, tt.upper, begin, end)
+		}
+		if idx, ok := BinarySearch(v, tt.x); idx != tt.lower || ok != tt.found {
+			t.Errorf("BinarySearch(%d) expected (%d,%v), got (%d,%v)", tt.x, tt.lower, tt.found, idx, ok)
+		}
+	}
+
+	empty := NewVector[int]()
+	if LowerBound(empty, 1) != 0 || UpperBound(empty, 1) != 0 {
+		t.Errorf("Expected bounds on empty vector to be 0")
+	}
+}
+
+func TestBoundsFunc(t *testing.T) {
+	// 按降序排列
+	desc := func(a, b int) int { return b - a }
+	v := NewVector(9, 7, 7, 4, 2)
+
+	if got := v.LowerBoundFunc(7, desc); got != 1 {
+		t.Errorf("LowerBoundFunc(7) expected 1, got %d", got)
+	}
+	if got := v.UpperBoundFunc(7, desc); got != 3 {
+		t.Errorf("UpperBoundFunc(7) expected 3, got %d", got)
+	}
+	if begin, end := v.EqualRangeFunc(5, desc); begin != 3 || end != 3 {
+		t.Errorf("EqualRangeFunc(5) expected [3,3), got [%d,%d)", begin, end)
+	}
+	if idx, ok := v.BinarySearchFunc(2, desc); idx != 4 || !ok {
+		t.Errorf("BinarySearchFunc(2) expected (4,true), got (%d,%v)", idx, ok)
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	v := NewVector[int]()
+	for _, x := range []int{5, 1, 4, 1, 3, 9} {
+		InsertSorted(v, x)
+	}
+	expected := []int{1, 1, 3, 4, 5, 9}
+	for i, val := range expected {
+		if v.At(i) != val {
+			t.Fatalf("Expected v[%d] to be %d, got %d", i, val, v.At(i))
+		}
+	}
+
+	type item struct {
+		key   int
+		label string
+	}
+	byKey := func(a, b item) int { return a.key - b.key }
+	items := NewVector(item{1, "a"}, item{2, "b"})
+	if idx := items.InsertSortedFunc(item{1, "c"}, byKey); idx != 1 {
+		t.Errorf("InsertSortedFunc should insert after equal elements, got index %d", idx)
+	}
+	if items.At(0).label != "a" || items.At(1).label != "c" || items.At(2).label != "b" {
+		t.Errorf("Unexpected order after InsertSortedFunc: %v", items.ToSlice())
+	}
+}
@@ -0,0 +1,132 @@
+package wsdeque_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Repeater11/go-template/structure/wsdeque"
+)
+
+// task 是调度器执行的任务，可以通过 spawn 派生新的子任务。
+type task func(spawn func(task))
+
+// scheduler 是一个最小的工作窃取调度器：每个 worker 拥有一个本地 Deque，
+// 在底部压入和弹出自己的任务，本地任务耗尽时从其他 worker 的顶部窃取。
+type scheduler struct {
+	deques   []*wsdeque.Deque[task]
+	pending  atomic.Int64 // 已提交但尚未执行完毕的任务数
+	executed []atomic.Int64
+}
+
+func newScheduler(workers int) *scheduler {
+	s := &scheduler{
+		deques:   make([]*wsdeque.Deque[task], workers),
+		executed: make([]atomic.Int64, workers),
+	}
+	for i := range s.deques {
+		s.deques[i] = wsdeque.NewDeque[task]()
+	}
+	return s
+}
+
+// run 把初始任务放入第 0 个 worker 的队列，并阻塞直到所有任务执行完毕。
+func (s *scheduler) run(tasks ...task) {
+	for _, t := range tasks {
+		s.pending.Add(1)
+		s.deques[0].PushBottom(t)
+	}
+
+	var wg sync.WaitGroup
+	for id := range s.deques {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(id)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *scheduler) work(id int) {
+	local := s.deques[id]
+	spawn := func(t task) {
+		s.pending.Add(1)
+		local.PushBottom(t)
+	}
+	for s.pending.Load() > 0 {
+		t, ok := local.PopBottom()
+		if !ok {
+			t, ok = s.steal(id)
+		}
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		t(spawn)
+		s.executed[id].Add(1)
+		s.pending.Add(-1)
+	}
+}
+
+func (s *scheduler) steal(thief int) (task, bool) {
+	for i := 1; i < len(s.deques); i++ {
+		victim := s.deques[(thief+i)%len(s.deques)]
+		if t, ok := victim.Steal(); ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// fib 以分治方式计算斐波那契数，每次拆分都会派生两个子任务。
+func fib(n int, result *atomic.Int64) task {
+	return func(spawn func(task)) {
+		if n < 2 {
+			result.Add(int64(n))
+			return
+		}
+		spawn(fib(n-1, result))
+		spawn(fib(n-2, result))
+	}
+}
+
+func Example() {
+	var result atomic.Int64
+	s := newScheduler(4)
+	s.run(fib(15, &result))
+	fmt.Println(result.Load())
+	// Output: 610
+}
+
+// TestSchedulerBalancesLoad 将所有任务都放在一个 worker 上，验证空闲 worker 会窃取任务。
+func TestSchedulerBalancesLoad(t *testing.T) {
+	const tasks = 200
+	s := newScheduler(4)
+
+	var done atomic.Int64
+	work := make([]task, tasks)
+	for i := range work {
+		work[i] = func(func(task)) {
+			time.Sleep(50 * time.Microsecond)
+			done.Add(1)
+		}
+	}
+	s.run(work...)
+
+	if done.Load() != tasks {
+		t.Fatalf("expected %d tasks executed, got %d", tasks, done.Load())
+	}
+	busy := 0
+	for i := range s.executed {
+		if s.executed[i].Load() > 0 {
+			busy++
+		}
+	}
+	if busy < 2 {
+		t.Fatalf("expected work to be spread across workers, only %d worker(s) ran tasks", busy)
+	}
+}
@@ -0,0 +1,133 @@
+// Package wsdeque 提供了用于任务调度的 Chase-Lev 工作窃取双端队列实现。
+package wsdeque
+
+import "sync/atomic"
+
+// initialCapacity 是循环数组的初始容量，必须为 2 的幂。
+const initialCapacity = 32
+
+// array 是一个容量为 2 的幂的循环数组，下标对容量取模后访问。
+type array[T any] struct {
+	buf  []atomic.Pointer[T]
+	mask int64
+}
+
+// newArray 创建指定容量的循环数组。
+func newArray[T any](capacity int64) *array[T] {
+	return &array[T]{
+		buf:  make([]atomic.Pointer[T], capacity),
+		mask: capacity - 1,
+	}
+}
+
+// get 返回逻辑下标 i 处的元素。
+func (a *array[T]) get(i int64) *T {
+	return a.buf[i&a.mask].Load()
+}
+
+// put 设置逻辑下标 i 处的元素。
+func (a *array[T]) put(i int64, elem *T) {
+	a.buf[i&a.mask].Store(elem)
+}
+
+// grow 返回一个容量翻倍并包含 [top, bottom) 内元素的新数组。
+// 旧数组保持不变，正在读取旧数组的窃取者仍能得到正确的值。
+func (a *array[T]) grow(bottom, top int64) *array[T] {
+	next := newArray[T](int64(len(a.buf)) * 2)
+	for i := top; i < bottom; i++ {
+		next.put(i, a.get(i))
+	}
+	return next
+}
+
+// Deque 是一个 Chase-Lev 工作窃取双端队列。
+// 只有拥有者 goroutine 可以调用 PushBottom 和 PopBottom，
+// 其他任意数量的 goroutine 可以并发调用 Steal 从顶部窃取元素。
+// 底层循环数组在空间不足时自动扩容。
+type Deque[T any] struct {
+	top    atomic.Int64 // 窃取者取元素的位置
+	bottom atomic.Int64 // 拥有者放入元素的下一个位置
+	array  atomic.Pointer[array[T]]
+}
+
+// NewDeque 创建并返回一个新的空 Deque。
+func NewDeque[T any]() *Deque[T] {
+	d := &Deque[T]{}
+	d.array.Store(newArray[T](initialCapacity))
+	return d
+}
+
+// Len 返回 Deque 中元素的数量。
+// 在并发窃取期间返回的只是一个近似值。
+func (d *Deque[T]) Len() int {
+	n := d.bottom.Load() - d.top.Load()
+	return int(max(n, 0))
+}
+
+// IsEmpty 检查 Deque 是否为空。
+// 在并发窃取期间返回的只是一个近似值。
+func (d *Deque[T]) IsEmpty() bool {
+	return d.Len() == 0
+}
+
+// PushBottom 在底部添加一个元素，只能由拥有者调用。
+func (d *Deque[T]) PushBottom(elem T) {
+	b := d.bottom.Load()
+	t := d.top.Load()
+	a := d.array.Load()
+	if b-t >= int64(len(a.buf)) {
+		a = a.grow(b, t)
+		d.array.Store(a)
+	}
+	a.put(b, &elem)
+	d.bottom.Store(b + 1)
+}
+
+// PopBottom 从底部移除并返回一个元素，只能由拥有者调用。
+// 如果 Deque 为空或最后一个元素被窃取者抢先取走，返回零值和 false。
+func (d *Deque[T]) PopBottom() (T, bool) {
+	var zero T
+	b := d.bottom.Load() - 1
+	a := d.array.Load()
+	// 先预留底部元素，再读取 top，与 Steal 中的顺序相反
+	d.bottom.Store(b)
+	t := d.top.Load()
+
+	if t > b {
+		// 队列为空，恢复 bottom
+		d.bottom.Store(b + 1)
+		return zero, false
+	}
+
+	elem := a.get(b)
+	if t < b {
+		// 至少还剩一个元素，不会与窃取者竞争
+		return *elem, true
+	}
+
+	// 只剩最后一个元素，需要与窃取者竞争 top
+	won := d.top.CompareAndSwap(t, t+1)
+	d.bottom.Store(b + 1)
+	if !won {
+		return zero, false
+	}
+	return *elem, true
+}
+
+// Steal 从顶部移除并返回一个元素，可以由任意 goroutine 并发调用。
+// 如果 Deque 为空，返回零值和 false。
+func (d *Deque[T]) Steal() (T, bool) {
+	for {
+		t := d.top.Load()
+		b := d.bottom.Load()
+		if t >= b {
+			var zero T
+			return zero, false
+		}
+		elem := d.array.Load().get(t)
+		if d.top.CompareAndSwap(t, t+1) {
+			return *elem, true
+		}
+		// 与其他窃取者或拥有者竞争失败，重试
+	}
+}
@@ -0,0 +1,120 @@
+package wsdeque
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOwnerOperations(t *testing.T) {
+	d := NewDeque[int]()
+	if !d.IsEmpty() || d.Len() != 0 {
+		t.Fatal("new deque should be empty")
+	}
+	if _, ok := d.PopBottom(); ok {
+		t.Fatal("PopBottom on empty deque should fail")
+	}
+	if _, ok := d.Steal(); ok {
+		t.Fatal("Steal on empty deque should fail")
+	}
+
+	for i := 0; i < 5; i++ {
+		d.PushBottom(i)
+	}
+	if d.Len() != 5 {
+		t.Fatalf("expected len 5, got %d", d.Len())
+	}
+	if v, ok := d.PopBottom(); !ok || v != 4 {
+		t.Fatalf("PopBottom expected (4,true), got (%d,%v)", v, ok)
+	}
+	if v, ok := d.Steal(); !ok || v != 0 {
+		t.Fatalf("Steal expected (0,true), got (%d,%v)", v, ok)
+	}
+	for _, want := range []int{3, 2, 1} {
+		if v, ok := d.PopBottom(); !ok || v != want {
+			t.Fatalf("PopBottom expected (%d,true), got (%d,%v)", want, v, ok)
+		}
+	}
+	if !d.IsEmpty() {
+		t.Fatal("deque should be empty")
+	}
+}
+
+func TestGrow(t *testing.T) {
+	d := NewDeque[int]()
+	const n = initialCapacity*4 + 3
+	for i := 0; i < n; i++ {
+		d.PushBottom(i)
+		if i%5 == 0 {
+			// 让 top 前移，使扩容时的 [top, bottom) 跨越数组边界
+			if v, ok := d.Steal(); !ok || v != i/5 {
+				t.Fatalf("Steal expected %d, got %d", i/5, v)
+			}
+		}
+	}
+	stolen := (n-1)/5 + 1
+	for i := stolen; i < n; i++ {
+		if v, ok := d.Steal(); !ok || v != i {
+			t.Fatalf("Steal expected %d, got %d", i, v)
+		}
+	}
+}
+
+// TestConcurrentSteal 验证拥有者与多个窃取者并发操作时，每个元素恰好被取出一次。
+func TestConcurrentSteal(t *testing.T) {
+	const (
+		items    = 20000
+		stealers = 4
+	)
+	d := NewDeque[int]()
+	var taken [items]atomic.Int32
+	var done atomic.Bool
+	var wg sync.WaitGroup
+
+	for s := 0; s < stealers; s++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() || !d.IsEmpty() {
+				if v, ok := d.Steal(); ok {
+					taken[v].Add(1)
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < items; i++ {
+		d.PushBottom(i)
+		if i%3 == 0 {
+			if v, ok := d.PopBottom(); ok {
+				taken[v].Add(1)
+			}
+		}
+	}
+	for {
+		v, ok := d.PopBottom()
+		if !ok {
+			break
+		}
+		taken[v].Add(1)
+	}
+	done.Store(true)
+	wg.Wait()
+
+	for i := range taken {
+		if n := taken[i].Load(); n != 1 {
+			t.Fatalf("item %d taken %d times", i, n)
+		}
+	}
+}
+
+func BenchmarkPushPopBottom(b *testing.B) {
+	d := NewDeque[int]()
+	for i := 0; i < b.N; i++ {
+		d.PushBottom(i)
+		d.PopBottom()
+	}
+}